@@ -5,12 +5,17 @@ package awsclient
 import (
 	"context"
 	"io"
+	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
 type Client interface {
@@ -25,16 +30,31 @@ type Client interface {
 	DeleteMessage(ctx context.Context, queueURL, receiptHandle string) error
 }
 
+// RouterClient is the subset of SQS operations used by a Router. *AWSClient
+// implements it.
+type RouterClient interface {
+	ReceiveMessages(ctx context.Context, queueURL string, maxMessages int32) ([]Message, error)
+	DeleteMessage(ctx context.Context, queueURL, receiptHandle string) error
+	ChangeMessageVisibility(ctx context.Context, queueURL, receiptHandle string, timeout time.Duration) error
+}
+
 // Message represents an SQS message.
 type Message struct {
 	ID            string
 	Body          string
 	ReceiptHandle string
+	// Attributes holds the message's string and number attributes.
+	Attributes map[string]string
+	// ReceiveCount is how many times the message has been received, including
+	// this time.
+	ReceiveCount int
 }
 
 type AWSClient struct {
 	s3Client  *s3.Client
 	sqsClient *sqs.Client
+	stsClient *sts.Client
+	awsCfg    aws.Config
 	cfg       *Config
 }
 
@@ -60,6 +80,7 @@ func New(ctx context.Context, cfg *Config) (*AWSClient, error) {
 
 	s3Opts := []func(*s3.Options){}
 	sqsOpts := []func(*sqs.Options){}
+	stsOpts := []func(*sts.Options){}
 
 	if cfg.Endpoint != "" {
 		s3Opts = append(s3Opts, func(o *s3.Options) {
@@ -69,35 +90,56 @@ func New(ctx context.Context, cfg *Config) (*AWSClient, error) {
 		sqsOpts = append(sqsOpts, func(o *sqs.Options) {
 			o.BaseEndpoint = aws.String(cfg.Endpoint)
 		})
+		stsOpts = append(stsOpts, func(o *sts.Options) {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+		})
 	}
 
 	return &AWSClient{
 		s3Client:  s3.NewFromConfig(awsCfg, s3Opts...),
 		sqsClient: sqs.NewFromConfig(awsCfg, sqsOpts...),
+		stsClient: sts.NewFromConfig(awsCfg, stsOpts...),
+		awsCfg:    awsCfg,
 		cfg:       cfg,
 	}, nil
 }
 
-// PutObject uploads an object to S3.
+// PutObject uploads an object to S3 with the default options.
 func (c *AWSClient) PutObject(ctx context.Context, bucket, key string, body io.Reader) error {
-	_, err := c.s3Client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket: aws.String(bucket),
-		Key:    aws.String(key),
-		Body:   body,
-	})
-	return err
+	return c.PutObjectWithOptions(ctx, bucket, key, body)
 }
 
-// GetObject retrieves an object from S3.
+// PutObjectWithOptions uploads an object to S3. A CRC32 checksum is sent
+// unless opts select another algorithm with WithChecksum. It is not part of
+// Client; callers holding a Client can type-assert to *AWSClient.
+func (c *AWSClient) PutObjectWithOptions(ctx context.Context, bucket, key string, body io.Reader, opts ...PutOption) error {
+	input := &s3.PutObjectInput{
+		Bucket:            aws.String(bucket),
+		Key:               aws.String(key),
+		Body:              body,
+		ChecksumAlgorithm: ChecksumCRC32,
+	}
+	for _, opt := range opts {
+		opt(input)
+	}
+
+	_, err := c.s3Client.PutObject(ctx, input)
+	return classify(err)
+}
+
+// GetObject retrieves an object from S3. If the object was stored with a
+// checksum, the body is verified as it is read and a read returns
+// ErrChecksumMismatch if the content does not match.
 func (c *AWSClient) GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
 	output, err := c.s3Client.GetObject(ctx, &s3.GetObjectInput{
-		Bucket: aws.String(bucket),
-		Key:    aws.String(key),
+		Bucket:       aws.String(bucket),
+		Key:          aws.String(key),
+		ChecksumMode: types.ChecksumModeEnabled,
 	})
 	if err != nil {
-		return nil, err
+		return nil, classify(err)
 	}
-	return output.Body, nil
+	return checksumReader{output.Body}, nil
 }
 
 // DeleteObject removes an object from S3.
@@ -106,7 +148,7 @@ func (c *AWSClient) DeleteObject(ctx context.Context, bucket, key string) error
 		Bucket: aws.String(bucket),
 		Key:    aws.String(key),
 	})
-	return err
+	return classify(err)
 }
 
 // SendMessage sends a message to an SQS queue.
@@ -116,7 +158,7 @@ func (c *AWSClient) SendMessage(ctx context.Context, queueURL, messageBody strin
 		MessageBody: aws.String(messageBody),
 	})
 	if err != nil {
-		return "", err
+		return "", classify(err)
 	}
 	return *output.MessageId, nil
 }
@@ -124,19 +166,33 @@ func (c *AWSClient) SendMessage(ctx context.Context, queueURL, messageBody strin
 // ReceiveMessages receives messages from an SQS queue.
 func (c *AWSClient) ReceiveMessages(ctx context.Context, queueURL string, maxMessages int32) ([]Message, error) {
 	output, err := c.sqsClient.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
-		QueueUrl:            aws.String(queueURL),
-		MaxNumberOfMessages: maxMessages,
+		QueueUrl:              aws.String(queueURL),
+		MaxNumberOfMessages:   maxMessages,
+		MessageAttributeNames: []string{"All"},
+		MessageSystemAttributeNames: []sqstypes.MessageSystemAttributeName{
+			sqstypes.MessageSystemAttributeNameApproximateReceiveCount,
+		},
 	})
 	if err != nil {
-		return nil, err
+		return nil, classify(err)
 	}
 
 	messages := make([]Message, len(output.Messages))
 	for i, msg := range output.Messages {
+		attrs := make(map[string]string, len(msg.MessageAttributes))
+		for name, v := range msg.MessageAttributes {
+			if v.StringValue != nil {
+				attrs[name] = *v.StringValue
+			}
+		}
+		receiveCount, _ := strconv.Atoi(msg.Attributes[string(sqstypes.MessageSystemAttributeNameApproximateReceiveCount)])
+
 		messages[i] = Message{
 			ID:            *msg.MessageId,
 			Body:          *msg.Body,
 			ReceiptHandle: *msg.ReceiptHandle,
+			Attributes:    attrs,
+			ReceiveCount:  receiveCount,
 		}
 	}
 	return messages, nil
@@ -148,5 +204,23 @@ func (c *AWSClient) DeleteMessage(ctx context.Context, queueURL, receiptHandle s
 		QueueUrl:      aws.String(queueURL),
 		ReceiptHandle: aws.String(receiptHandle),
 	})
-	return err
+	return classify(err)
+}
+
+// ChangeMessageVisibility sets how long until a received message becomes
+// visible to consumers again. SQS counts in whole seconds, so timeout is
+// rounded up, and it is capped at the 12 hour maximum.
+func (c *AWSClient) ChangeMessageVisibility(ctx context.Context, queueURL, receiptHandle string, timeout time.Duration) error {
+	_, err := c.sqsClient.ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          aws.String(queueURL),
+		ReceiptHandle:     aws.String(receiptHandle),
+		VisibilityTimeout: visibilitySeconds(timeout),
+	})
+	return classify(err)
+}
+
+// visibilitySeconds converts timeout to the whole seconds SQS accepts.
+func visibilitySeconds(timeout time.Duration) int32 {
+	timeout = min(max(timeout, 0), maxVisibilityTimeout)
+	return int32((timeout + time.Second - 1) / time.Second)
 }
@@ -0,0 +1,63 @@
+// Command awsdiag prints how aws-client resolves its configuration from the
+// environment and checks access to the given buckets and queues. It exits
+// non-zero if any check fails, so it can run as a deployment smoke test.
+//
+//	awsdiag -bucket uploads -queue https://sqs.us-east-1.amazonaws.com/123456789012/jobs
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	awsclient "github.com/bpurdy1/golang-packages/aws-client"
+)
+
+// listFlag collects a repeatable or comma-separated flag.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(v string) error {
+	*l = append(*l, strings.Split(v, ",")...)
+	return nil
+}
+
+func main() {
+	var buckets, queues listFlag
+	flag.Var(&buckets, "bucket", "S3 bucket to check (repeatable)")
+	flag.Var(&queues, "queue", "SQS queue URL to check (repeatable)")
+	timeout := flag.Duration("timeout", 30*time.Second, "overall timeout")
+	flag.Parse()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	if err := run(ctx, buckets, queues); err != nil {
+		fmt.Fprintln(os.Stderr, "awsdiag:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, buckets, queues []string) error {
+	cfg, err := awsclient.LoadConfig()
+	if err != nil {
+		return err
+	}
+	client, err := awsclient.New(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	diag, err := client.Diagnose(ctx,
+		awsclient.WithBucketChecks(buckets...),
+		awsclient.WithQueueChecks(queues...),
+	)
+	fmt.Print(diag)
+	return err
+}
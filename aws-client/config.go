@@ -1,22 +1,22 @@
 package awsclient
 
 import (
-	"github.com/caarlos0/env/v11"
+	"github.com/bpurdy1/golang-packages/envparse"
 )
 
 // Config holds AWS configuration loaded from environment variables.
 type Config struct {
 	Region          string `env:"AWS_REGION" envDefault:"us-east-1"`
 	AccessKeyID     string `env:"AWS_ACCESS_KEY_ID"`
-	SecretAccessKey string `env:"AWS_SECRET_ACCESS_KEY"`
-	SessionToken    string `env:"AWS_SESSION_TOKEN"`
+	SecretAccessKey string `env:"AWS_SECRET_ACCESS_KEY" sensitive:"true"`
+	SessionToken    string `env:"AWS_SESSION_TOKEN" sensitive:"true"`
 	Endpoint        string `env:"AWS_ENDPOINT"` // For localstack/testing
 }
 
 // LoadConfig loads AWS configuration from environment variables.
 func LoadConfig() (*Config, error) {
 	cfg := &Config{}
-	if err := env.Parse(cfg); err != nil {
+	if err := envparse.Parse(cfg); err != nil {
 		return nil, err
 	}
 	return cfg, nil
@@ -0,0 +1,144 @@
+package awsclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// Diagnosis describes how an AWSClient resolved its configuration and whether
+// it can reach the resources a deployment depends on.
+type Diagnosis struct {
+	Region string
+	// CredentialSource names the provider that supplied credentials, such as
+	// "StaticCredentials", "EnvConfigCredentials" or "EC2RoleProvider".
+	CredentialSource string
+	Account          string
+	CallerARN        string
+	// Endpoint is the endpoint override (e.g. localstack), if any.
+	Endpoint string
+	Checks   []DiagnosticCheck
+}
+
+// DiagnosticCheck is the result of one permission smoke test.
+type DiagnosticCheck struct {
+	// Name is the API call exercised, e.g. "s3:HeadBucket".
+	Name     string
+	Resource string
+	Err      error
+}
+
+// Err joins the errors of every failed check.
+func (d *Diagnosis) Err() error {
+	var errs []error
+	for _, c := range d.Checks {
+		if c.Err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", c.Name, c.Resource, c.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// String formats the diagnosis as a human-readable report.
+func (d *Diagnosis) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "region:            %s\n", d.Region)
+	fmt.Fprintf(&sb, "credential source: %s\n", valueOr(d.CredentialSource, "(none)"))
+	fmt.Fprintf(&sb, "account:           %s\n", valueOr(d.Account, "(unknown)"))
+	fmt.Fprintf(&sb, "caller:            %s\n", valueOr(d.CallerARN, "(unknown)"))
+	fmt.Fprintf(&sb, "endpoint:          %s\n", valueOr(d.Endpoint, "(default)"))
+	for _, c := range d.Checks {
+		status := "ok"
+		if c.Err != nil {
+			status = "FAIL: " + c.Err.Error()
+		}
+		fmt.Fprintf(&sb, "%-18s %s %s\n", c.Name, c.Resource, status)
+	}
+	return sb.String()
+}
+
+func valueOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+type diagnoseOptions struct {
+	buckets []string
+	queues  []string
+}
+
+type DiagnoseOption func(*diagnoseOptions)
+
+// WithBucketChecks checks that each bucket exists and that the caller may
+// list it (s3:ListBucket).
+func WithBucketChecks(buckets ...string) DiagnoseOption {
+	return func(o *diagnoseOptions) {
+		o.buckets = append(o.buckets, buckets...)
+	}
+}
+
+// WithQueueChecks checks that each queue exists and that the caller may read
+// its attributes (sqs:GetQueueAttributes).
+func WithQueueChecks(queueURLs ...string) DiagnoseOption {
+	return func(o *diagnoseOptions) {
+		o.queues = append(o.queues, queueURLs...)
+	}
+}
+
+// Diagnose resolves credentials, asks STS who the caller is and runs
+// read-only permission checks, so misconfigured deployments fail at startup
+// rather than on first use. The returned error covers credential and STS
+// failures as well as failed checks; the Diagnosis is returned either way.
+//
+//	diag, err := client.Diagnose(ctx, awsclient.WithBucketChecks("uploads"))
+//	if err != nil {
+//		log.Fatalf("aws misconfigured:\n%s\n%v", diag, err)
+//	}
+func (c *AWSClient) Diagnose(ctx context.Context, opts ...DiagnoseOption) (*Diagnosis, error) {
+	var o diagnoseOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	d := &Diagnosis{
+		Region:   c.awsCfg.Region,
+		Endpoint: c.cfg.Endpoint,
+	}
+
+	creds, err := c.awsCfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return d, fmt.Errorf("no usable AWS credentials: set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY, AWS_PROFILE, or attach an IAM role: %w", err)
+	}
+	d.CredentialSource = creds.Source
+
+	identity, err := c.stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return d, fmt.Errorf("sts:GetCallerIdentity failed for %s credentials, they may be expired or for the wrong partition: %w",
+			creds.Source, classify(err))
+	}
+	d.Account = aws.ToString(identity.Account)
+	d.CallerARN = aws.ToString(identity.Arn)
+
+	for _, bucket := range o.buckets {
+		_, err := c.s3Client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(bucket)})
+		d.Checks = append(d.Checks, DiagnosticCheck{Name: "s3:HeadBucket", Resource: bucket, Err: classify(err)})
+	}
+	for _, queueURL := range o.queues {
+		_, err := c.sqsClient.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+			QueueUrl:       aws.String(queueURL),
+			AttributeNames: []sqstypes.QueueAttributeName{sqstypes.QueueAttributeNameQueueArn},
+		})
+		d.Checks = append(d.Checks, DiagnosticCheck{Name: "sqs:GetQueueAttributes", Resource: queueURL, Err: classify(err)})
+	}
+
+	return d, d.Err()
+}
@@ -0,0 +1,75 @@
+package awsclient_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	awsclient "github.com/bpurdy1/golang-packages/aws-client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const callerIdentityResponse = `<GetCallerIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <GetCallerIdentityResult>
+    <Arn>arn:aws:iam::123456789012:user/deployer</Arn>
+    <UserId>AIDAEXAMPLE</UserId>
+    <Account>123456789012</Account>
+  </GetCallerIdentityResult>
+  <ResponseMetadata><RequestId>req-1</RequestId></ResponseMetadata>
+</GetCallerIdentityResponse>`
+
+func TestDiagnose(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case strings.Contains(string(body), "Action=GetCallerIdentity"):
+			_, _ = io.WriteString(w, callerIdentityResponse)
+		case r.Header.Get("X-Amz-Target") == "AmazonSQS.GetQueueAttributes":
+			w.Header().Set("X-Amzn-Query-Error", "AWS.SimpleQueueService.NonExistentQueue;Sender")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"__type":"com.amazonaws.sqs#QueueDoesNotExist","message":"The specified queue does not exist."}`)
+		case r.Method == http.MethodHead && r.URL.Path == "/uploads":
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	})
+
+	diag, err := client.Diagnose(context.Background(),
+		awsclient.WithBucketChecks("uploads", "secrets"),
+		awsclient.WithQueueChecks("http://localhost/123456789012/missing"),
+	)
+	require.NotNil(t, diag)
+
+	assert.Equal(t, "us-east-1", diag.Region)
+	assert.Equal(t, "StaticCredentials", diag.CredentialSource)
+	assert.Equal(t, "123456789012", diag.Account)
+	assert.Equal(t, "arn:aws:iam::123456789012:user/deployer", diag.CallerARN)
+
+	require.Len(t, diag.Checks, 3)
+	assert.NoError(t, diag.Checks[0].Err)
+	assert.ErrorIs(t, diag.Checks[1].Err, awsclient.ErrAccessDenied)
+	assert.ErrorIs(t, diag.Checks[2].Err, awsclient.ErrQueueNotFound)
+
+	assert.ErrorIs(t, err, awsclient.ErrAccessDenied)
+	assert.ErrorIs(t, err, awsclient.ErrQueueNotFound)
+	assert.Contains(t, err.Error(), "s3:HeadBucket secrets")
+	assert.Contains(t, diag.String(), "caller:            arn:aws:iam::123456789012:user/deployer")
+}
+
+func TestDiagnose_STSFailure(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = io.WriteString(w, `<ErrorResponse><Error><Type>Sender</Type><Code>InvalidClientTokenId</Code><Message>The security token included in the request is invalid.</Message></Error></ErrorResponse>`)
+	})
+
+	diag, err := client.Diagnose(context.Background(), awsclient.WithBucketChecks("uploads"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sts:GetCallerIdentity")
+	assert.Equal(t, "StaticCredentials", diag.CredentialSource)
+	assert.Empty(t, diag.Checks)
+}
@@ -0,0 +1,86 @@
+package awsclient
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go"
+)
+
+var (
+	ErrNoSuchKey     = errors.New("awsclient: no such key")
+	ErrAccessDenied  = errors.New("awsclient: access denied")
+	ErrThrottled     = errors.New("awsclient: request throttled")
+	ErrQueueNotFound = errors.New("awsclient: queue not found")
+)
+
+// errorCodes maps AWS error codes to package errors. Throttling codes are
+// taken from the SDK's retryer. S3 HEAD responses have no body, so their code
+// is the HTTP status text (NotFound, Forbidden).
+var errorCodes = map[string]error{
+	"NoSuchKey":             ErrNoSuchKey,
+	"NotFound":              ErrNoSuchKey,
+	"AccessDenied":          ErrAccessDenied,
+	"AccessDeniedException": ErrAccessDenied,
+	"Forbidden":             ErrAccessDenied,
+	"QueueDoesNotExist":     ErrQueueNotFound,
+	"AWS.SimpleQueueService.NonExistentQueue": ErrQueueNotFound,
+	"BadDigest": ErrChecksumMismatch,
+}
+
+// Error is an AWS API error classified into one of the package errors. It
+// matches both the package error and the original SDK error with errors.Is
+// and errors.As.
+//
+//	var awsErr *awsclient.Error
+//	if errors.Is(err, awsclient.ErrNoSuchKey) && errors.As(err, &awsErr) {
+//		log.Printf("missing object (request id %s)", awsErr.RequestID)
+//	}
+type Error struct {
+	Kind      error
+	Code      string
+	RequestID string
+	Err       error
+}
+
+func (e *Error) Error() string {
+	return e.Kind.Error() + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// RequestID returns the AWS request ID carried by err, or "" if there is none.
+func RequestID(err error) string {
+	var reqErr interface{ ServiceRequestID() string }
+	if errors.As(err, &reqErr) {
+		return reqErr.ServiceRequestID()
+	}
+	return ""
+}
+
+// classify wraps SDK errors with a known error code in an *Error. Other errors
+// are returned unchanged.
+func classify(err error) error {
+	var apiErr smithy.APIError
+	if err == nil || !errors.As(err, &apiErr) {
+		return err
+	}
+
+	code := apiErr.ErrorCode()
+	kind, ok := errorCodes[code]
+	if !ok {
+		if _, throttled := retry.DefaultThrottleErrorCodes[code]; !throttled {
+			return err
+		}
+		kind = ErrThrottled
+	}
+
+	return &Error{
+		Kind:      kind,
+		Code:      code,
+		RequestID: RequestID(err),
+		Err:       err,
+	}
+}
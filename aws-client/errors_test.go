@@ -0,0 +1,71 @@
+package awsclient_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	awsclient "github.com/bpurdy1/golang-packages/aws-client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func s3ErrorHandler(status int, code string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("X-Amz-Request-Id", "req-123")
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, "<Error><Code>"+code+"</Code><Message>failed</Message></Error>")
+	}
+}
+
+func TestClassify_S3Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		code   string
+		want   error
+	}{
+		{name: "no such key", status: http.StatusNotFound, code: "NoSuchKey", want: awsclient.ErrNoSuchKey},
+		{name: "access denied", status: http.StatusForbidden, code: "AccessDenied", want: awsclient.ErrAccessDenied},
+		{name: "throttled", status: http.StatusServiceUnavailable, code: "SlowDown", want: awsclient.ErrThrottled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, s3ErrorHandler(tt.status, tt.code))
+			_, err := client.GetObject(context.Background(), "bucket", "key")
+			require.Error(t, err)
+			assert.ErrorIs(t, err, tt.want)
+
+			var awsErr *awsclient.Error
+			require.True(t, errors.As(err, &awsErr))
+			assert.Equal(t, tt.code, awsErr.Code)
+			assert.Equal(t, "req-123", awsErr.RequestID)
+			assert.Equal(t, "req-123", awsclient.RequestID(err))
+		})
+	}
+}
+
+func TestClassify_KeepsSDKError(t *testing.T) {
+	client := newTestClient(t, s3ErrorHandler(http.StatusNotFound, "NoSuchKey"))
+
+	_, err := client.GetObject(context.Background(), "bucket", "key")
+
+	var noSuchKey *types.NoSuchKey
+	assert.True(t, errors.As(err, &noSuchKey))
+}
+
+func TestClassify_Unknown(t *testing.T) {
+	client := newTestClient(t, s3ErrorHandler(http.StatusBadRequest, "InvalidArgument"))
+
+	err := client.DeleteObject(context.Background(), "bucket", "key")
+	require.Error(t, err)
+
+	var awsErr *awsclient.Error
+	assert.False(t, errors.As(err, &awsErr))
+	assert.Equal(t, "req-123", awsclient.RequestID(err))
+}
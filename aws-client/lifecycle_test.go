@@ -0,0 +1,69 @@
+package awsclient_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	awsclient "github.com/bpurdy1/golang-packages/aws-client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyLifecycleRules(t *testing.T) {
+	const existing = `<LifecycleConfiguration>
+<Rule><ID>keep-logs</ID><Status>Enabled</Status><Filter><Prefix>logs/</Prefix></Filter><Expiration><Days>30</Days></Expiration></Rule>
+<Rule><ID>archive-exports</ID><Status>Enabled</Status><Filter><Prefix>old/</Prefix></Filter><Expiration><Days>1</Days></Expiration></Rule>
+</LifecycleConfiguration>`
+
+	var put string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = io.WriteString(w, existing)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		put = string(b)
+	})
+
+	err := client.ApplyLifecycleRules(context.Background(), "reports", awsclient.LifecycleRule{
+		ID:     "archive-exports",
+		Prefix: "exports/",
+		Transitions: []awsclient.LifecycleTransition{
+			{AfterDays: 30, StorageClass: awsclient.StorageStandardIA},
+			{AfterDays: 90, StorageClass: awsclient.StorageGlacier},
+		},
+		ExpireAfterDays: 365,
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, put, "<ID>keep-logs</ID>")
+	assert.Equal(t, 1, strings.Count(put, "<ID>archive-exports</ID>"))
+	assert.NotContains(t, put, "old/")
+	assert.Contains(t, put, "<Prefix>exports/</Prefix>")
+	assert.Contains(t, put, "<Transition><Days>30</Days><StorageClass>STANDARD_IA</StorageClass></Transition>")
+	assert.Contains(t, put, "<Expiration><Days>365</Days></Expiration>")
+}
+
+func TestApplyLifecycleRules_NoExistingConfiguration(t *testing.T) {
+	var put string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, "<Error><Code>NoSuchLifecycleConfiguration</Code></Error>")
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		put = string(b)
+	})
+
+	err := client.ApplyLifecycleRules(context.Background(), "reports", awsclient.LifecycleRule{
+		ID:              "expire-tmp",
+		Prefix:          "tmp/",
+		ExpireAfterDays: 7,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, put, "<ID>expire-tmp</ID>")
+}
@@ -13,6 +13,7 @@ import (
 	context "context"
 	io "io"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 
@@ -129,3 +130,70 @@ func (mr *MockClientMockRecorder) SendMessage(ctx, queueURL, messageBody any) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockClient)(nil).SendMessage), ctx, queueURL, messageBody)
 }
+
+// MockRouterClient is a mock of RouterClient interface.
+type MockRouterClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockRouterClientMockRecorder
+	isgomock struct{}
+}
+
+// MockRouterClientMockRecorder is the mock recorder for MockRouterClient.
+type MockRouterClientMockRecorder struct {
+	mock *MockRouterClient
+}
+
+// NewMockRouterClient creates a new mock instance.
+func NewMockRouterClient(ctrl *gomock.Controller) *MockRouterClient {
+	mock := &MockRouterClient{ctrl: ctrl}
+	mock.recorder = &MockRouterClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRouterClient) EXPECT() *MockRouterClientMockRecorder {
+	return m.recorder
+}
+
+// ChangeMessageVisibility mocks base method.
+func (m *MockRouterClient) ChangeMessageVisibility(ctx context.Context, queueURL, receiptHandle string, timeout time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeMessageVisibility", ctx, queueURL, receiptHandle, timeout)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangeMessageVisibility indicates an expected call of ChangeMessageVisibility.
+func (mr *MockRouterClientMockRecorder) ChangeMessageVisibility(ctx, queueURL, receiptHandle, timeout any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeMessageVisibility", reflect.TypeOf((*MockRouterClient)(nil).ChangeMessageVisibility), ctx, queueURL, receiptHandle, timeout)
+}
+
+// DeleteMessage mocks base method.
+func (m *MockRouterClient) DeleteMessage(ctx context.Context, queueURL, receiptHandle string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessage", ctx, queueURL, receiptHandle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMessage indicates an expected call of DeleteMessage.
+func (mr *MockRouterClientMockRecorder) DeleteMessage(ctx, queueURL, receiptHandle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessage", reflect.TypeOf((*MockRouterClient)(nil).DeleteMessage), ctx, queueURL, receiptHandle)
+}
+
+// ReceiveMessages mocks base method.
+func (m *MockRouterClient) ReceiveMessages(ctx context.Context, queueURL string, maxMessages int32) ([]awsclient.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReceiveMessages", ctx, queueURL, maxMessages)
+	ret0, _ := ret[0].([]awsclient.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReceiveMessages indicates an expected call of ReceiveMessages.
+func (mr *MockRouterClientMockRecorder) ReceiveMessages(ctx, queueURL, maxMessages any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReceiveMessages", reflect.TypeOf((*MockRouterClient)(nil).ReceiveMessages), ctx, queueURL, maxMessages)
+}
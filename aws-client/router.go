@@ -0,0 +1,245 @@
+package awsclient
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// RouteHandler processes a message of one type. Returning nil deletes the
+// message; returning an error leaves it on the queue to be retried.
+type RouteHandler func(ctx context.Context, msg Message) error
+
+type route struct {
+	handler     RouteHandler
+	concurrency int
+	maxAttempts int
+	backoff     time.Duration
+}
+
+type RouteOption func(*route)
+
+// WithConcurrency sets how many messages of the type are handled at once
+// (default: 1). Values below 1 are treated as 1.
+func WithConcurrency(n int) RouteOption {
+	return func(r *route) {
+		r.concurrency = max(n, 1)
+	}
+}
+
+// WithMaxAttempts deletes a message after it has failed n times. By default
+// failed messages are retried until the queue's redrive policy moves them to a
+// dead-letter queue.
+func WithMaxAttempts(n int) RouteOption {
+	return func(r *route) {
+		r.maxAttempts = n
+	}
+}
+
+// WithRetryBackoff delays the retry of a failed message by base, doubling with
+// each attempt, instead of waiting for the queue's visibility timeout.
+func WithRetryBackoff(base time.Duration) RouteOption {
+	return func(r *route) {
+		r.backoff = base
+	}
+}
+
+// Router receives messages from one SQS queue and dispatches them to handlers
+// registered per message type. The type is read from a message attribute, or
+// from a "type" field when the body is a JSON object.
+//
+//	router := awsclient.NewRouter(client, queueURL)
+//	router.Handle("user.created", onUserCreated, awsclient.WithConcurrency(4))
+//	router.Handle("user.deleted", onUserDeleted, awsclient.WithMaxAttempts(3))
+//	err := router.Run(ctx)
+type Router struct {
+	client       RouterClient
+	queueURL     string
+	typeKey      string
+	batchSize    int32
+	pollInterval time.Duration
+	logger       *slog.Logger
+	routes       map[string]*route
+}
+
+type RouterOption func(*Router)
+
+// WithTypeKey sets the message attribute and JSON field holding the message
+// type (default: "type").
+func WithTypeKey(key string) RouterOption {
+	return func(r *Router) {
+		r.typeKey = key
+	}
+}
+
+// WithRouterBatchSize sets the maximum number of messages fetched per receive
+// call (default: 10).
+func WithRouterBatchSize(n int32) RouterOption {
+	return func(r *Router) {
+		r.batchSize = n
+	}
+}
+
+// WithRouterPollInterval sets how long Run waits after an empty receive
+// (default: 1s).
+func WithRouterPollInterval(d time.Duration) RouterOption {
+	return func(r *Router) {
+		r.pollInterval = d
+	}
+}
+
+// WithRouterLogger sets the logger used for handler failures and unroutable
+// messages (default: slog.Default()).
+func WithRouterLogger(logger *slog.Logger) RouterOption {
+	return func(r *Router) {
+		r.logger = logger
+	}
+}
+
+// NewRouter creates a Router for the queue at queueURL.
+func NewRouter(client RouterClient, queueURL string, opts ...RouterOption) *Router {
+	r := &Router{
+		client:       client,
+		queueURL:     queueURL,
+		typeKey:      "type",
+		batchSize:    10,
+		pollInterval: time.Second,
+		logger:       slog.Default(),
+		routes:       make(map[string]*route),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Handle registers handler for messages of msgType. It must be called before
+// Run.
+func (r *Router) Handle(msgType string, handler RouteHandler, opts ...RouteOption) {
+	rt := &route{
+		handler:     handler,
+		concurrency: 1,
+	}
+	for _, opt := range opts {
+		opt(rt)
+	}
+	r.routes[msgType] = rt
+}
+
+// Run receives and dispatches messages until ctx is cancelled, then waits for
+// in-flight handlers to return. Messages with no registered handler are left
+// on the queue.
+//
+// Each type is handled by its own workers, so a slow handler does not hold up
+// received messages of other types. Up to one batch of messages per type waits
+// for busy workers; once that is full, Run stops receiving until one of them
+// is free.
+func (r *Router) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	queues := make(map[string]chan Message, len(r.routes))
+	for msgType, rt := range r.routes {
+		queue := make(chan Message, max(rt.concurrency, int(r.batchSize)))
+		queues[msgType] = queue
+		for range rt.concurrency {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for msg := range queue {
+					// Messages still queued at shutdown return to SQS.
+					if ctx.Err() == nil {
+						r.dispatch(ctx, rt, msgType, msg)
+					}
+				}
+			}()
+		}
+	}
+	defer func() {
+		for _, queue := range queues {
+			close(queue)
+		}
+		wg.Wait()
+	}()
+
+	for {
+		msgs, err := r.client.ReceiveMessages(ctx, r.queueURL, r.batchSize)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		for _, msg := range msgs {
+			msgType := r.messageType(msg)
+			queue, ok := queues[msgType]
+			if !ok {
+				r.logger.Warn("no handler for message type", "type", msgType, "message_id", msg.ID)
+				continue
+			}
+
+			select {
+			case queue <- msg:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+
+		if len(msgs) == 0 {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(r.pollInterval):
+			}
+		}
+	}
+}
+
+func (r *Router) messageType(msg Message) string {
+	if t, ok := msg.Attributes[r.typeKey]; ok {
+		return t
+	}
+
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(msg.Body), &envelope); err != nil {
+		return ""
+	}
+	var t string
+	_ = json.Unmarshal(envelope[r.typeKey], &t)
+	return t
+}
+
+func (r *Router) dispatch(ctx context.Context, rt *route, msgType string, msg Message) {
+	err := rt.handler(ctx, msg)
+	if err == nil {
+		if err := r.client.DeleteMessage(ctx, r.queueURL, msg.ReceiptHandle); err != nil {
+			r.logger.Error("failed to delete message", "type", msgType, "message_id", msg.ID, "error", err)
+		}
+		return
+	}
+
+	logger := r.logger.With("type", msgType, "message_id", msg.ID, "attempt", msg.ReceiveCount, "error", err)
+	if rt.maxAttempts > 0 && msg.ReceiveCount >= rt.maxAttempts {
+		logger.Error("message failed on final attempt, deleting")
+		if err := r.client.DeleteMessage(ctx, r.queueURL, msg.ReceiptHandle); err != nil {
+			logger.Error("failed to delete message", "delete_error", err)
+		}
+		return
+	}
+
+	logger.Warn("message handler failed")
+	if rt.backoff > 0 {
+		delay := rt.backoff
+		for i := 1; i < msg.ReceiveCount && delay < maxVisibilityTimeout; i++ {
+			delay *= 2
+		}
+		delay = min(delay, maxVisibilityTimeout)
+		if err := r.client.ChangeMessageVisibility(ctx, r.queueURL, msg.ReceiptHandle, delay); err != nil {
+			logger.Error("failed to delay retry", "visibility_error", err)
+		}
+	}
+}
+
+// maxVisibilityTimeout is the longest visibility timeout SQS accepts.
+const maxVisibilityTimeout = 12 * time.Hour
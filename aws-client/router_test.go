@@ -0,0 +1,211 @@
+package awsclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	awsclient "github.com/bpurdy1/golang-packages/aws-client"
+	"github.com/bpurdy1/golang-packages/aws-client/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRouter_Dispatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockRouterClient(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	queueURL := "https://sqs.us-east-1.amazonaws.com/123456789/events"
+	messages := []awsclient.Message{
+		{ID: "1", Body: "{}", ReceiptHandle: "h1", Attributes: map[string]string{"type": "user.created"}, ReceiveCount: 1},
+		{ID: "2", Body: `{"type":"user.deleted","id":7}`, ReceiptHandle: "h2", ReceiveCount: 2},
+		{ID: "3", Body: `{"type":"user.deleted","id":8}`, ReceiptHandle: "h3", ReceiveCount: 3},
+		{ID: "4", Body: `{"type":"unknown"}`, ReceiptHandle: "h4", ReceiveCount: 1},
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+
+	gomock.InOrder(
+		mockClient.EXPECT().ReceiveMessages(ctx, queueURL, int32(10)).Return(messages, nil),
+		mockClient.EXPECT().ReceiveMessages(ctx, queueURL, int32(10)).
+			DoAndReturn(func(ctx context.Context, _ string, _ int32) ([]awsclient.Message, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}),
+	)
+	// Handled successfully.
+	mockClient.EXPECT().DeleteMessage(ctx, queueURL, "h1").DoAndReturn(func(context.Context, string, string) error {
+		wg.Done()
+		return nil
+	})
+	// Failed on the second attempt: retried after 2 * base.
+	mockClient.EXPECT().ChangeMessageVisibility(ctx, queueURL, "h2", 2*time.Second).DoAndReturn(func(context.Context, string, string, time.Duration) error {
+		wg.Done()
+		return nil
+	})
+	// Failed on the final attempt: deleted.
+	mockClient.EXPECT().DeleteMessage(ctx, queueURL, "h3").DoAndReturn(func(context.Context, string, string) error {
+		wg.Done()
+		return nil
+	})
+
+	router := awsclient.NewRouter(mockClient, queueURL)
+	router.Handle("user.created", func(ctx context.Context, msg awsclient.Message) error {
+		return nil
+	}, awsclient.WithConcurrency(2))
+	router.Handle("user.deleted", func(ctx context.Context, msg awsclient.Message) error {
+		return errors.New("downstream unavailable")
+	}, awsclient.WithMaxAttempts(3), awsclient.WithRetryBackoff(time.Second))
+
+	done := make(chan error)
+	go func() { done <- router.Run(ctx) }()
+
+	wg.Wait()
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestRouter_SlowTypeDoesNotBlockOthers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockRouterClient(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	queueURL := "https://sqs.us-east-1.amazonaws.com/123456789/events"
+	messages := []awsclient.Message{
+		{ID: "1", Body: "{}", ReceiptHandle: "s1", Attributes: map[string]string{"type": "report"}},
+		{ID: "2", Body: "{}", ReceiptHandle: "s2", Attributes: map[string]string{"type": "report"}},
+		{ID: "3", Body: "{}", ReceiptHandle: "s3", Attributes: map[string]string{"type": "report"}},
+		{ID: "4", Body: "{}", ReceiptHandle: "f1", Attributes: map[string]string{"type": "email"}},
+	}
+
+	gomock.InOrder(
+		mockClient.EXPECT().ReceiveMessages(ctx, queueURL, int32(10)).Return(messages, nil),
+		mockClient.EXPECT().ReceiveMessages(ctx, queueURL, int32(10)).
+			DoAndReturn(func(ctx context.Context, _ string, _ int32) ([]awsclient.Message, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}),
+	)
+	emailDone := make(chan struct{})
+	mockClient.EXPECT().DeleteMessage(ctx, queueURL, "f1").DoAndReturn(func(context.Context, string, string) error {
+		close(emailDone)
+		return nil
+	})
+	// Queued reports are handled, or returned to the queue at shutdown.
+	mockClient.EXPECT().DeleteMessage(ctx, queueURL, gomock.Any()).Return(nil).AnyTimes()
+
+	release := make(chan struct{})
+	var reports sync.WaitGroup
+	reports.Add(1)
+	var once sync.Once
+	router := awsclient.NewRouter(mockClient, queueURL)
+	router.Handle("report", func(ctx context.Context, msg awsclient.Message) error {
+		once.Do(reports.Done)
+		<-release
+		return nil
+	})
+	// A concurrency below 1 is treated as 1 instead of never running.
+	router.Handle("email", func(ctx context.Context, msg awsclient.Message) error {
+		return nil
+	}, awsclient.WithConcurrency(0))
+
+	done := make(chan error)
+	go func() { done <- router.Run(ctx) }()
+
+	reports.Wait()
+	select {
+	case <-emailDone:
+	case <-time.After(time.Second):
+		t.Fatal("email message waited for the report handler")
+	}
+	close(release)
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestRouter_StopsReceivingWhileBusy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockRouterClient(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	queueURL := "https://sqs.us-east-1.amazonaws.com/123456789/events"
+	var receives atomic.Int32
+	mockClient.EXPECT().ReceiveMessages(ctx, queueURL, int32(1)).
+		DoAndReturn(func(ctx context.Context, _ string, _ int32) ([]awsclient.Message, error) {
+			n := receives.Add(1)
+			if n > 3 {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			id := strconv.Itoa(int(n))
+			return []awsclient.Message{{ID: id, Body: "{}", ReceiptHandle: "h" + id, Attributes: map[string]string{"type": "report"}}}, nil
+		}).AnyTimes()
+	mockClient.EXPECT().DeleteMessage(ctx, queueURL, gomock.Any()).Return(nil).AnyTimes()
+
+	release := make(chan struct{})
+	router := awsclient.NewRouter(mockClient, queueURL, awsclient.WithRouterBatchSize(1))
+	router.Handle("report", func(ctx context.Context, msg awsclient.Message) error {
+		<-release
+		return nil
+	})
+
+	done := make(chan error)
+	go func() { done <- router.Run(ctx) }()
+
+	// One message is running, one is queued and the third waits for room, so
+	// no further receive is made until the handler returns.
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(3), receives.Load())
+
+	close(release)
+	assert.Eventually(t, func() bool { return receives.Load() == 4 }, time.Second, time.Millisecond)
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestChangeMessageVisibility_WholeSeconds(t *testing.T) {
+	tests := []struct {
+		timeout time.Duration
+		want    int
+	}{
+		{0, 0},
+		{200 * time.Millisecond, 1},
+		{time.Second, 1},
+		{1500 * time.Millisecond, 2},
+		{24 * time.Hour, 43200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.timeout.String(), func(t *testing.T) {
+			var got struct{ VisibilityTimeout int }
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&got)
+				w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+				_, _ = io.WriteString(w, "{}")
+			})
+
+			err := client.ChangeMessageVisibility(context.Background(), "https://sqs.us-east-1.amazonaws.com/123456789/events", "h1", tt.timeout)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.VisibilityTimeout)
+		})
+	}
+}
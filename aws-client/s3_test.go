@@ -0,0 +1,135 @@
+package awsclient_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	awsclient "github.com/bpurdy1/golang-packages/aws-client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *awsclient.AWSClient {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	client, err := awsclient.New(context.Background(), &awsclient.Config{
+		Region:          "us-east-1",
+		AccessKeyID:     "test",
+		SecretAccessKey: "test",
+		Endpoint:        srv.URL,
+	})
+	require.NoError(t, err)
+	return client
+}
+
+func TestPutObject_Options(t *testing.T) {
+	var got http.Header
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		_, _ = io.Copy(io.Discard, r.Body)
+	})
+
+	err := client.PutObjectWithOptions(context.Background(), "bucket", "key", strings.NewReader("hello"),
+		awsclient.WithContentType("text/plain"),
+		awsclient.WithCacheControl("max-age=60"),
+		awsclient.WithSSEKMS("alias/app"),
+		awsclient.WithChecksum(awsclient.ChecksumSHA256),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "text/plain", got.Get("Content-Type"))
+	assert.Equal(t, "max-age=60", got.Get("Cache-Control"))
+	assert.Equal(t, "aws:kms", got.Get("X-Amz-Server-Side-Encryption"))
+	assert.Equal(t, "alias/app", got.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"))
+	// sha256("hello"), base64 encoded.
+	assert.Equal(t, "LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ=", got.Get("X-Amz-Checksum-Sha256"))
+}
+
+func TestPutObject_DefaultChecksum(t *testing.T) {
+	var got http.Header
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		_, _ = io.Copy(io.Discard, r.Body)
+	})
+
+	err := client.PutObject(context.Background(), "bucket", "key", strings.NewReader("hello"))
+	require.NoError(t, err)
+	// crc32("hello"), base64 encoded.
+	assert.Equal(t, "NhCmhg==", got.Get("X-Amz-Checksum-Crc32"))
+}
+
+func TestPutObject_SSES3(t *testing.T) {
+	var got http.Header
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		_, _ = io.Copy(io.Discard, r.Body)
+	})
+
+	err := client.PutObjectWithOptions(context.Background(), "bucket", "key", strings.NewReader("hello"), awsclient.WithSSES3())
+	require.NoError(t, err)
+	assert.Equal(t, "AES256", got.Get("X-Amz-Server-Side-Encryption"))
+}
+
+func TestPutObject_ChecksumMismatch(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `<Error><Code>BadDigest</Code><Message>The checksum did not match.</Message></Error>`)
+	})
+
+	err := client.PutObjectWithOptions(context.Background(), "bucket", "key", strings.NewReader("hello"),
+		awsclient.WithChecksum(awsclient.ChecksumCRC32))
+	assert.ErrorIs(t, err, awsclient.ErrChecksumMismatch)
+}
+
+func TestGetObject_Checksum(t *testing.T) {
+	tests := []struct {
+		name     string
+		checksum string
+		wantErr  error
+	}{
+		{name: "valid", checksum: "LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ="},
+		{name: "corrupted", checksum: "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=", wantErr: awsclient.ErrChecksumMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "ENABLED", r.Header.Get("X-Amz-Checksum-Mode"))
+				w.Header().Set("X-Amz-Checksum-Sha256", tt.checksum)
+				_, _ = io.WriteString(w, "hello")
+			})
+
+			body, err := client.GetObject(context.Background(), "bucket", "key")
+			require.NoError(t, err)
+			defer body.Close()
+
+			data, err := io.ReadAll(body)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "hello", string(data))
+		})
+	}
+}
+
+func TestPutObject_StorageClass(t *testing.T) {
+	var got http.Header
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		_, _ = io.Copy(io.Discard, r.Body)
+	})
+
+	err := client.PutObjectWithOptions(context.Background(), "bucket", "key", strings.NewReader("hello"),
+		awsclient.WithStorageClass(awsclient.StorageGlacierIR))
+	require.NoError(t, err)
+	assert.Equal(t, "GLACIER_IR", got.Get("X-Amz-Storage-Class"))
+}
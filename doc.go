@@ -5,6 +5,7 @@
 //   - pg-client: PostgreSQL client wrapper with environment configuration
 //   - nats-client: NATS client wrapper with environment configuration
 //   - aws-client: AWS client wrapper with environment configuration
+//   - queue: Transport-agnostic message queue backed by SQS or NATS
 //   - waitgroup: WaitGroup with concurrency limiting
 //   - sqlutils: SQL utility functions
 //   - parallel: Parallel execution utilities
@@ -0,0 +1,100 @@
+package envparse
+
+import (
+	"log/slog"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var (
+	deprecationsMu sync.RWMutex
+	deprecations   = make(map[string][]string)
+	warned         sync.Map
+)
+
+// Deprecate registers old as a deprecated name for the variable key. When key
+// is unset and old is set, Parse uses the value of old and logs a warning.
+// Packages call it from init to rename a variable without breaking
+// deployments that still set the old name.
+//
+//	func init() { envparse.Deprecate("REDIS_PASS", "REDIS_PASSWORD") }
+//
+// The envAlias tag declares the same on a single field and accepts a comma
+// separated list:
+//
+//	Password string `env:"REDIS_PASSWORD" envAlias:"REDIS_PASS"`
+func Deprecate(old, key string) {
+	deprecationsMu.Lock()
+	defer deprecationsMu.Unlock()
+	deprecations[key] = append(deprecations[key], old)
+}
+
+// environment returns the process environment with the deprecated names
+// declared for cfg, via envAlias tags or Deprecate, mapped to their
+// replacements.
+func environment(cfg any) map[string]string {
+	environ := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			environ[key] = value
+		}
+	}
+
+	aliases := make(map[string][]string)
+	collectAliases(reflect.TypeOf(cfg), "", aliases)
+
+	deprecationsMu.RLock()
+	for key, olds := range deprecations {
+		aliases[key] = append(aliases[key], olds...)
+	}
+	deprecationsMu.RUnlock()
+
+	for key, olds := range aliases {
+		if _, ok := environ[key]; ok {
+			continue
+		}
+		for _, old := range olds {
+			value, ok := environ[old]
+			if !ok {
+				continue
+			}
+			environ[key] = value
+			if _, seen := warned.LoadOrStore(old, true); !seen {
+				slog.Warn("envparse: deprecated environment variable, rename it",
+					"name", old, "replacement", key)
+			}
+			break
+		}
+	}
+	return environ
+}
+
+// collectAliases records the envAlias names of every field of t, following
+// nested structs and their envPrefix.
+func collectAliases(t reflect.Type, prefix string, aliases map[string][]string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key, _, _ := strings.Cut(field.Tag.Get("env"), ",")
+		if key == "" {
+			collectAliases(field.Type, prefix+field.Tag.Get("envPrefix"), aliases)
+			continue
+		}
+
+		alias := field.Tag.Get("envAlias")
+		if alias == "" {
+			continue
+		}
+		for _, old := range strings.Split(alias, ",") {
+			aliases[prefix+key] = append(aliases[prefix+key], prefix+strings.TrimSpace(old))
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package envparse
+
+import "testing"
+
+type aliasConfig struct {
+	Password string `env:"TEST_ALIAS_PASSWORD" envAlias:"TEST_ALIAS_PASS, TEST_ALIAS_PW"`
+	Nested   struct {
+		Host string `env:"HOST" envAlias:"HOSTNAME"`
+	} `envPrefix:"TEST_ALIAS_DB_"`
+}
+
+func TestParse_EnvAlias(t *testing.T) {
+	t.Setenv("TEST_ALIAS_PW", "hunter2")
+	t.Setenv("TEST_ALIAS_DB_HOSTNAME", "db.internal")
+
+	var cfg aliasConfig
+	if err := Parse(&cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Password != "hunter2" {
+		t.Errorf("Password = %q, want %q", cfg.Password, "hunter2")
+	}
+	if cfg.Nested.Host != "db.internal" {
+		t.Errorf("Host = %q, want %q", cfg.Nested.Host, "db.internal")
+	}
+}
+
+func TestParse_EnvAliasPrefersNewName(t *testing.T) {
+	t.Setenv("TEST_ALIAS_PASSWORD", "new")
+	t.Setenv("TEST_ALIAS_PASS", "old")
+
+	var cfg aliasConfig
+	if err := Parse(&cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Password != "new" {
+		t.Errorf("Password = %q, want %q", cfg.Password, "new")
+	}
+}
+
+func TestDeprecate(t *testing.T) {
+	Deprecate("TEST_DEPRECATED_URL", "TEST_DEPRECATE_URL")
+	t.Setenv("TEST_DEPRECATED_URL", "redis://cache:6379")
+
+	var cfg struct {
+		URL string `env:"TEST_DEPRECATE_URL,required"`
+	}
+	if err := Parse(&cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.URL != "redis://cache:6379" {
+		t.Errorf("URL = %q, want %q", cfg.URL, "redis://cache:6379")
+	}
+}
@@ -0,0 +1,43 @@
+// Command envgen scaffolds a Go config struct from a .env file.
+//
+//	go run github.com/bpurdy1/golang-packages/envparse/cmd/envgen -in .env -pkg config -type Config > config/config.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/bpurdy1/golang-packages/envparse"
+)
+
+func main() {
+	in := flag.String("in", ".env", "path of the .env file to read")
+	pkg := flag.String("pkg", "config", "package name of the generated file")
+	typeName := flag.String("type", "Config", "name of the generated struct")
+	out := flag.String("out", "", "file to write (default: stdout)")
+	flag.Parse()
+
+	if err := run(*in, *pkg, *typeName, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "envgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, pkg, typeName, out string) error {
+	keys, err := envparse.ReadEnvFile(in)
+	if err != nil {
+		return err
+	}
+
+	src, err := envparse.GenerateStruct(keys, pkg, typeName)
+	if err != nil {
+		return err
+	}
+
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(out, src, 0o644)
+}
@@ -0,0 +1,165 @@
+package envparse
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// initialisms are kept upper case in generated field names.
+var initialisms = map[string]bool{
+	"API": true, "AWS": true, "DB": true, "DNS": true, "HTTP": true, "HTTPS": true,
+	"ID": true, "IP": true, "JSON": true, "JWT": true, "SQL": true, "SQS": true,
+	"SSL": true, "TCP": true, "TLS": true, "TTL": true, "URI": true, "URL": true,
+}
+
+// ReadEnvFile reads KEY=value pairs from a .env file, as written by
+// ToEnvFile. Blank lines, comments and an optional "export " prefix are
+// ignored, and values may be wrapped in single or double quotes.
+func ReadEnvFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	vars := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected KEY=value", path, n)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		vars[strings.TrimSpace(key)] = value
+	}
+	return vars, scanner.Err()
+}
+
+// GenerateStruct emits Go source for a config struct with one env-tagged
+// field per key, to scaffold a Config from an existing .env file. Field types
+// are inferred from the values (bool, int, float64, time.Duration, []string
+// for comma-separated lists, otherwise string) and values become envDefault
+// tags. Keys and values that look like credentials, such as passwords, tokens
+// and URLs with a password, get a sensitive tag instead of a default; review
+// these before committing the struct. Keys that map to the same field name,
+// such as REDIS-ADDR and REDIS_ADDR, are an error.
+func GenerateStruct(keys map[string]string, pkg, typeName string) ([]byte, error) {
+	names := make([]string, 0, len(keys))
+	for k := range keys {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	fields := make(map[string]string, len(names))
+	for _, key := range names {
+		name := fieldName(key)
+		if other, ok := fields[name]; ok {
+			return nil, fmt.Errorf("envparse: keys %q and %q both map to field %s", other, key, name)
+		}
+		fields[name] = key
+	}
+
+	var body bytes.Buffer
+	usesTime := false
+	for _, key := range names {
+		value := keys[key]
+		typ, sep := inferType(value)
+		if typ == "time.Duration" {
+			usesTime = true
+		}
+
+		tag := fmt.Sprintf(`env:%q`, key)
+		if sep {
+			tag += ` envSeparator:","`
+		}
+		if looksSecret(key, value) {
+			tag += ` sensitive:"true"`
+		} else if value != "" {
+			tag += fmt.Sprintf(` envDefault:%q`, value)
+		}
+		fmt.Fprintf(&body, "\t%s %s `%s`\n", fieldName(key), typ, tag)
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "package %s\n\n", pkg)
+	if usesTime {
+		src.WriteString("import \"time\"\n\n")
+	}
+	fmt.Fprintf(&src, "// %s holds settings loaded from environment variables.\n", typeName)
+	fmt.Fprintf(&src, "type %s struct {\n%s}\n", typeName, body.String())
+
+	return format.Source(src.Bytes())
+}
+
+// secretMarkers are key fragments GenerateStruct treats as credentials.
+var secretMarkers = []string{"PASS", "SECRET", "TOKEN", "KEY", "CREDENTIAL", "CERT", "PRIVATE", "DSN"}
+
+func looksSecret(key, value string) bool {
+	key = strings.ToUpper(key)
+	for _, marker := range secretMarkers {
+		if strings.Contains(key, marker) {
+			return true
+		}
+	}
+	if u, err := url.Parse(value); err == nil && u.User != nil {
+		_, hasPassword := u.User.Password()
+		return hasPassword
+	}
+	return false
+}
+
+func inferType(value string) (typ string, separated bool) {
+	switch {
+	case value == "":
+		return "string", false
+	case value == "true" || value == "false":
+		return "bool", false
+	}
+	if _, err := strconv.Atoi(value); err == nil {
+		return "int", false
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return "float64", false
+	}
+	if _, err := time.ParseDuration(value); err == nil {
+		return "time.Duration", false
+	}
+	if strings.Contains(value, ",") && !strings.ContainsAny(value, " =") {
+		return "[]string", true
+	}
+	return "string", false
+}
+
+// fieldName converts an environment key such as REDIS_TLS_CERT_PATH into an
+// exported Go identifier (RedisTLSCertPath).
+func fieldName(key string) string {
+	var sb strings.Builder
+	for _, part := range strings.FieldsFunc(key, func(r rune) bool { return r == '_' || r == '-' || r == '.' }) {
+		upper := strings.ToUpper(part)
+		if initialisms[upper] {
+			sb.WriteString(upper)
+			continue
+		}
+		sb.WriteString(upper[:1] + strings.ToLower(part[1:]))
+	}
+	name := sb.String()
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "Env" + name
+	}
+	return name
+}
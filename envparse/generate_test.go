@@ -0,0 +1,67 @@
+package envparse
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateStruct(t *testing.T) {
+	src, err := GenerateStruct(map[string]string{
+		"REDIS_ADDR":          "localhost:6379",
+		"REDIS_PASS":          "hunter2",
+		"REDIS_URL":           "redis://:hunter2@localhost:6379",
+		"REDIS_DB":            "0",
+		"REDIS_TLS":           "false",
+		"REDIS_TIMEOUT":       "5s",
+		"REDIS_REPLICA_ADDRS": "a:6379,b:6379",
+	}, "config", "RedisConfig")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := string(src)
+
+	for _, want := range []string{
+		"package config\n",
+		`import "time"`,
+		"type RedisConfig struct {",
+		"RedisAddr         string        `env:\"REDIS_ADDR\" envDefault:\"localhost:6379\"`",
+		"RedisDB           int           `env:\"REDIS_DB\" envDefault:\"0\"`",
+		"RedisTLS          bool          `env:\"REDIS_TLS\" envDefault:\"false\"`",
+		"RedisTimeout      time.Duration `env:\"REDIS_TIMEOUT\" envDefault:\"5s\"`",
+		"RedisReplicaAddrs []string      `env:\"REDIS_REPLICA_ADDRS\" envSeparator:\",\" envDefault:\"a:6379,b:6379\"`",
+		"RedisPass         string        `env:\"REDIS_PASS\" sensitive:\"true\"`",
+		"RedisURL          string        `env:\"REDIS_URL\" sensitive:\"true\"`",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestGenerateStruct_FieldCollision(t *testing.T) {
+	_, err := GenerateStruct(map[string]string{
+		"REDIS-ADDR": "a:6379",
+		"REDIS_ADDR": "b:6379",
+	}, "config", "RedisConfig")
+	if err == nil || !strings.Contains(err.Error(), "RedisAddr") {
+		t.Errorf("expected collision error naming RedisAddr, got %v", err)
+	}
+}
+
+func TestReadEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	content := "# comment\n\nexport APP_NAME=\"my app\"\nAPP_PORT=8080\nAPP_EMPTY=\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	vars, err := ReadEnvFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(vars) != 3 || vars["APP_NAME"] != "my app" || vars["APP_PORT"] != "8080" || vars["APP_EMPTY"] != "" {
+		t.Errorf("unexpected vars: %v", vars)
+	}
+}
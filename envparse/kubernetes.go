@@ -0,0 +1,116 @@
+package envparse
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type manifestOptions struct {
+	secretValues bool
+}
+
+type ManifestOption func(*manifestOptions)
+
+// WithSecretValues writes the actual values of sensitive entries into the
+// Secret, base64 encoded, instead of placeholders. The values are not
+// encrypted: seal the output before committing it.
+func WithSecretValues() ManifestOption {
+	return func(o *manifestOptions) {
+		o.secretValues = true
+	}
+}
+
+// ToKubernetesManifests renders the configuration of every struct passed to
+// Parse as a ConfigMap and a Secret, see Registry.ToKubernetesManifests.
+func ToKubernetesManifests(name, namespace string, opts ...ManifestOption) string {
+	return reg.ToKubernetesManifests(name, namespace, opts...)
+}
+
+// ToKubernetesManifests renders the registered entries as a YAML ConfigMap
+// holding the non-sensitive entries and an Opaque Secret holding the
+// sensitive ones (see EnvEntry.Sensitive), separated by "---". Both are named
+// name; namespace is omitted when empty. A manifest with no entries is left
+// out.
+//
+// Secret values are rendered as ${KEY} placeholders under stringData, to be
+// filled in by envsubst or the deployment's secret tooling, unless
+// WithSecretValues is given.
+//
+//	yaml := envparse.ToKubernetesManifests("billing", "prod")
+//	os.WriteFile("deploy/billing-config.yaml", []byte(yaml), 0o644)
+func (r *Registry) ToKubernetesManifests(name, namespace string, opts ...ManifestOption) string {
+	var o manifestOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	entries := r.All()
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var config, secret []string
+	for _, key := range keys {
+		value := formatValue(entries[key].Value)
+		switch {
+		case !entries[key].Sensitive:
+			config = append(config, fmt.Sprintf("  %s: %s\n", key, strconv.Quote(value)))
+		case o.secretValues:
+			secret = append(secret, fmt.Sprintf("  %s: %q\n", key, base64.StdEncoding.EncodeToString([]byte(value))))
+		default:
+			secret = append(secret, fmt.Sprintf("  %s: %q\n", key, "${"+key+"}"))
+		}
+	}
+
+	var docs []string
+	if len(config) > 0 {
+		docs = append(docs, manifest("ConfigMap", name, namespace, "", "data", config))
+	}
+	if len(secret) > 0 {
+		field := "stringData"
+		if o.secretValues {
+			field = "data"
+		}
+		docs = append(docs, manifest("Secret", name, namespace, "Opaque", field, secret))
+	}
+	return strings.Join(docs, "---\n")
+}
+
+func manifest(kind, name, namespace, typ, field string, data []string) string {
+	var sb strings.Builder
+	sb.WriteString("apiVersion: v1\n")
+	sb.WriteString("kind: " + kind + "\n")
+	sb.WriteString("metadata:\n")
+	sb.WriteString("  name: " + strconv.Quote(name) + "\n")
+	if namespace != "" {
+		sb.WriteString("  namespace: " + strconv.Quote(namespace) + "\n")
+	}
+	if typ != "" {
+		sb.WriteString("type: " + typ + "\n")
+	}
+	sb.WriteString(field + ":\n")
+	for _, line := range data {
+		sb.WriteString(line)
+	}
+	return sb.String()
+}
+
+// formatValue renders v the way it would be written in the environment:
+// slices are joined with the default "," separator.
+func formatValue(v any) string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return fmt.Sprint(v)
+	}
+	parts := make([]string, rv.Len())
+	for i := range parts {
+		parts[i] = fmt.Sprint(rv.Index(i).Interface())
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,62 @@
+package envparse
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistry_ToKubernetesManifests(t *testing.T) {
+	r := NewRegistry()
+	r.Add("APP_HOSTS", EnvEntry{Key: "APP_HOSTS", Value: []string{"a", "b"}})
+	r.Add("APP_PORT", EnvEntry{Key: "APP_PORT", Value: 8080})
+	r.Add("DB_PASSWORD", EnvEntry{Key: "DB_PASSWORD", Value: "hunter2", Sensitive: true})
+
+	out := r.ToKubernetesManifests("billing", "prod")
+
+	want := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: "billing"
+  namespace: "prod"
+data:
+  APP_HOSTS: "a,b"
+  APP_PORT: "8080"
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: "billing"
+  namespace: "prod"
+type: Opaque
+stringData:
+  DB_PASSWORD: "${DB_PASSWORD}"
+`
+	if out != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestRegistry_ToKubernetesManifests_SecretValues(t *testing.T) {
+	r := NewRegistry()
+	r.Add("DB_PASSWORD", EnvEntry{Key: "DB_PASSWORD", Value: "hunter2", Sensitive: true})
+
+	out := r.ToKubernetesManifests("billing", "", WithSecretValues())
+
+	if !strings.Contains(out, "data:\n  DB_PASSWORD: \"aHVudGVyMg==\"\n") {
+		t.Errorf("expected base64 encoded value under data, got:\n%s", out)
+	}
+}
+
+func TestRegistry_ToKubernetesManifests_NoSecrets(t *testing.T) {
+	r := NewRegistry()
+	r.Add("APP_NAME", EnvEntry{Key: "APP_NAME", Value: "svc"})
+
+	out := r.ToKubernetesManifests("billing", "")
+
+	if strings.Contains(out, "Secret") || strings.Contains(out, "---") {
+		t.Errorf("expected only a ConfigMap, got:\n%s", out)
+	}
+	if strings.Contains(out, "namespace") {
+		t.Errorf("expected namespace to be omitted, got:\n%s", out)
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/caarlos0/env/v11"
 )
@@ -17,13 +20,15 @@ var (
 	reg = NewRegistry()
 )
 
-// Parse parses environment variables into the struct, registers them, and returns any error
+// Parse parses environment variables into the struct, registers them, and
+// runs its validators (see Validator and RegisterValidator), returning any error.
+// Deprecated variable names are honoured, see Deprecate.
 func Parse(cfg any) error {
-	if err := env.Parse(cfg); err != nil {
+	if err := env.ParseWithOptions(cfg, env.Options{Environment: environment(cfg)}); err != nil {
 		return err
 	}
 	reg.register(cfg)
-	return nil
+	return Validate(cfg)
 }
 
 func ToEnvFile(path string) error {
@@ -31,14 +36,25 @@ func ToEnvFile(path string) error {
 	return os.WriteFile(path, []byte(out), os.ModePerm)
 }
 
+// Describe returns the effective configuration of every struct passed to Parse,
+// grouped by the package that declared it.
+func Describe() string {
+	return reg.Describe()
+}
+
 type EnvEntry struct {
 	Key      string
 	Value    any
 	Default  string
 	Required bool
+	Package  string
+	// Sensitive entries are masked by Describe and rendered into a Secret by
+	// ToKubernetesManifests.
+	Sensitive bool
 }
 
 type Registry struct {
+	mu              sync.RWMutex
 	entries         map[string]EnvEntry
 	registeredTypes map[reflect.Type]bool
 }
@@ -51,26 +67,84 @@ func NewRegistry() *Registry {
 }
 
 func (r *Registry) Add(key string, entry EnvEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.entries[key] = entry
 }
 
 func (r *Registry) Get(key string) (EnvEntry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	e, ok := r.entries[key]
 	return e, ok
 }
 
 func (r *Registry) All() map[string]EnvEntry {
-	return r.entries
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	all := make(map[string]EnvEntry, len(r.entries))
+	for k, e := range r.entries {
+		all[k] = e
+	}
+	return all
 }
 
 func (r *Registry) ToEnv() string {
 	var sb strings.Builder
-	for key, entry := range r.entries {
+	for key, entry := range r.All() {
 		sb.WriteString(fmt.Sprintf("%s=%v\n", key, entry.Value))
 	}
 	return sb.String()
 }
 
+// Describe renders the registered entries grouped by package, sorted by key.
+// Values of sensitive entries are masked.
+func (r *Registry) Describe() string {
+	groups := make(map[string][]EnvEntry)
+	for _, entry := range r.All() {
+		groups[entry.Package] = append(groups[entry.Package], entry)
+	}
+
+	pkgs := make([]string, 0, len(groups))
+	for pkg := range groups {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Strings(pkgs)
+
+	var sb strings.Builder
+	for _, pkg := range pkgs {
+		entries := groups[pkg]
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+
+		sb.WriteString(pkg + "\n")
+		for _, e := range entries {
+			value := fmt.Sprint(e.Value)
+			if e.Sensitive && value != "" {
+				value = "********"
+			}
+			sb.WriteString(fmt.Sprintf("  %s=%s", e.Key, value))
+			if e.Default != "" {
+				sb.WriteString(fmt.Sprintf(" (default: %s)", e.Default))
+			}
+			if e.Required {
+				sb.WriteString(" (required)")
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// isSensitive reports whether a field is tagged as holding a credential:
+//
+//	APIKey string `env:"STRIPE_API_KEY" sensitive:"true"`
+//
+// Untagged fields are never treated as sensitive.
+func isSensitive(field reflect.StructField) bool {
+	sensitive, _ := strconv.ParseBool(field.Tag.Get("sensitive"))
+	return sensitive
+}
+
 func (r *Registry) register(s any) {
 	v := reflect.ValueOf(s)
 	if v.Kind() == reflect.Ptr {
@@ -78,10 +152,13 @@ func (r *Registry) register(s any) {
 	}
 	t := v.Type()
 
+	r.mu.Lock()
 	if r.registeredTypes[t] {
+		r.mu.Unlock()
 		return
 	}
 	r.registeredTypes[t] = true
+	r.mu.Unlock()
 
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
@@ -95,11 +172,14 @@ func (r *Registry) register(s any) {
 		key := parts[0]
 		required := len(parts) > 1 && parts[1] == "required"
 
+		value := v.Field(i).Interface()
 		entry := EnvEntry{
-			Key:      key,
-			Value:    v.Field(i).Interface(),
-			Default:  field.Tag.Get("envDefault"),
-			Required: required,
+			Key:       key,
+			Value:     value,
+			Default:   field.Tag.Get("envDefault"),
+			Required:  required,
+			Package:   t.PkgPath(),
+			Sensitive: isSensitive(field),
 		}
 
 		r.Add(key, entry)
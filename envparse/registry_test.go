@@ -0,0 +1,100 @@
+package envparse
+
+import (
+	"strings"
+	"testing"
+)
+
+type describeConfig struct {
+	Host     string `env:"TEST_DESCRIBE_HOST" envDefault:"localhost"`
+	Password string `env:"TEST_DESCRIBE_PASS" sensitive:"true"`
+	Name     string `env:"TEST_DESCRIBE_NAME,required"`
+}
+
+func TestRegistry_Describe(t *testing.T) {
+	t.Setenv("TEST_DESCRIBE_PASS", "hunter2")
+	t.Setenv("TEST_DESCRIBE_NAME", "app")
+
+	r := NewRegistry()
+	cfg := &describeConfig{Host: "localhost", Password: "hunter2", Name: "app"}
+	r.register(cfg)
+
+	out := r.Describe()
+
+	if !strings.HasPrefix(out, "github.com/bpurdy1/golang-packages/envparse\n") {
+		t.Errorf("expected output grouped under package path, got:\n%s", out)
+	}
+	if !strings.Contains(out, "  TEST_DESCRIBE_HOST=localhost (default: localhost)\n") {
+		t.Errorf("expected host entry with default, got:\n%s", out)
+	}
+	if strings.Contains(out, "hunter2") {
+		t.Errorf("expected password to be masked, got:\n%s", out)
+	}
+	if !strings.Contains(out, "  TEST_DESCRIBE_NAME=app (required)\n") {
+		t.Errorf("expected required marker, got:\n%s", out)
+	}
+
+	hostIdx := strings.Index(out, "TEST_DESCRIBE_HOST")
+	nameIdx := strings.Index(out, "TEST_DESCRIBE_NAME")
+	if hostIdx > nameIdx {
+		t.Error("expected entries sorted by key")
+	}
+}
+
+func TestParse_Registers(t *testing.T) {
+	t.Setenv("TEST_DESCRIBE_NAME", "svc")
+
+	var cfg describeConfig
+	if err := Parse(&cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entry, ok := reg.Get("TEST_DESCRIBE_NAME")
+	if !ok {
+		t.Fatal("expected TEST_DESCRIBE_NAME to be registered")
+	}
+	if entry.Value != "svc" {
+		t.Errorf("Value = %v, want %q", entry.Value, "svc")
+	}
+	if !strings.Contains(Describe(), "TEST_DESCRIBE_NAME=svc") {
+		t.Errorf("expected Describe to include parsed value, got:\n%s", Describe())
+	}
+}
+
+type sensitiveConfig struct {
+	APIKey   string `env:"TEST_SENSITIVE_STRIPE" sensitive:"true"`
+	CacheKey string `env:"TEST_SENSITIVE_CACHE_KEY" sensitive:"false"`
+	DSN      string `env:"TEST_SENSITIVE_DATABASE"`
+	Region   string `env:"TEST_SENSITIVE_REGION"`
+}
+
+func TestRegistry_Sensitive(t *testing.T) {
+	r := NewRegistry()
+	r.register(&sensitiveConfig{
+		APIKey:   "sk_live_123",
+		CacheKey: "users",
+		DSN:      "postgres://app:hunter2@db:5432/app",
+		Region:   "us-east-1",
+	})
+
+	tests := map[string]bool{
+		"TEST_SENSITIVE_STRIPE":    true, // tagged
+		"TEST_SENSITIVE_CACHE_KEY": false,
+		"TEST_SENSITIVE_DATABASE":  false, // untagged, despite the password in the URL
+		"TEST_SENSITIVE_REGION":    false,
+	}
+	for key, want := range tests {
+		entry, _ := r.Get(key)
+		if entry.Sensitive != want {
+			t.Errorf("%s: Sensitive = %v, want %v", key, entry.Sensitive, want)
+		}
+	}
+
+	out := r.Describe()
+	if strings.Contains(out, "sk_live_123") {
+		t.Errorf("expected tagged value to be masked, got:\n%s", out)
+	}
+	if !strings.Contains(out, "TEST_SENSITIVE_CACHE_KEY=users") {
+		t.Errorf("expected untagged value to be shown, got:\n%s", out)
+	}
+}
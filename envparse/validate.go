@@ -0,0 +1,84 @@
+package envparse
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Validator is implemented by config structs that check their own values.
+// Parse calls Validate after the environment has been bound.
+//
+//	func (c *Config) Validate() error {
+//		if c.TLSEnabled && c.CertPath == "" {
+//			return errors.New("REDIS_TLS_CERT is required when REDIS_TLS is enabled")
+//		}
+//		return nil
+//	}
+type Validator interface {
+	Validate() error
+}
+
+var (
+	validatorsMu sync.RWMutex
+	validators   = make(map[reflect.Type][]func(any) error)
+)
+
+// RegisterValidator registers fn to run whenever Parse binds a value of type
+// T, for config types that cannot implement Validator themselves.
+func RegisterValidator[T any](fn func(*T) error) {
+	t := reflect.TypeFor[T]()
+
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+	validators[t] = append(validators[t], func(v any) error {
+		return fn(v.(*T))
+	})
+}
+
+// Validate runs the Validate method and registered validators of cfg and of
+// any nested structs, and joins every failure into one error. Parse calls it
+// automatically; call it directly for configs loaded from other sources.
+func Validate(cfg any) error {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs []error
+	collectErrors(v, &errs)
+	return errors.Join(errs...)
+}
+
+func collectErrors(v reflect.Value, errs *[]error) {
+	s := v.Elem()
+	t := s.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := s.Field(i)
+		if !t.Field(i).IsExported() {
+			continue
+		}
+		switch {
+		case field.Kind() == reflect.Struct && field.CanAddr():
+			collectErrors(field.Addr(), errs)
+		case field.Kind() == reflect.Ptr && !field.IsNil() && field.Elem().Kind() == reflect.Struct:
+			collectErrors(field, errs)
+		}
+	}
+
+	if val, ok := v.Interface().(Validator); ok {
+		if err := val.Validate(); err != nil {
+			*errs = append(*errs, fmt.Errorf("%s: %w", t, err))
+		}
+	}
+
+	validatorsMu.RLock()
+	fns := validators[t]
+	validatorsMu.RUnlock()
+	for _, fn := range fns {
+		if err := fn(v.Interface()); err != nil {
+			*errs = append(*errs, fmt.Errorf("%s: %w", t, err))
+		}
+	}
+}
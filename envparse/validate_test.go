@@ -0,0 +1,61 @@
+package envparse
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type tlsConfig struct {
+	Enabled  bool   `env:"TEST_VALIDATE_TLS"`
+	CertPath string `env:"TEST_VALIDATE_TLS_CERT"`
+}
+
+func (c *tlsConfig) Validate() error {
+	if c.Enabled && c.CertPath == "" {
+		return errors.New("TEST_VALIDATE_TLS_CERT is required when TLS is enabled")
+	}
+	return nil
+}
+
+type serverConfig struct {
+	Port int `env:"TEST_VALIDATE_PORT" envDefault:"8080"`
+	TLS  tlsConfig
+}
+
+func TestParse_Validate(t *testing.T) {
+	t.Setenv("TEST_VALIDATE_TLS", "true")
+
+	var cfg tlsConfig
+	err := Parse(&cfg)
+	if err == nil || !strings.Contains(err.Error(), "TEST_VALIDATE_TLS_CERT is required") {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+
+	t.Setenv("TEST_VALIDATE_TLS_CERT", "/etc/tls/cert.pem")
+	if err := Parse(&cfg); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestParse_ValidateAggregates(t *testing.T) {
+	t.Setenv("TEST_VALIDATE_TLS", "true")
+	t.Setenv("TEST_VALIDATE_PORT", "0")
+
+	errPort := errors.New("port must be positive")
+	RegisterValidator(func(c *serverConfig) error {
+		if c.Port <= 0 {
+			return errPort
+		}
+		return nil
+	})
+
+	var cfg serverConfig
+	err := Parse(&cfg)
+	if !errors.Is(err, errPort) {
+		t.Errorf("expected registered validator error, got %v", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "TEST_VALIDATE_TLS_CERT is required") {
+		t.Errorf("expected nested struct validation error, got %v", err)
+	}
+}
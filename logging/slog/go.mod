@@ -2,4 +2,15 @@ module github.com/bpurdy1/golang-packages/logging/slog
 
 go 1.25.6
 
-require github.com/caarlos0/env/v11 v11.3.1
+require (
+	github.com/bpurdy1/golang-packages/envparse v0.0.0-00010101000000-000000000000
+	go.opentelemetry.io/otel/trace v1.46.0
+)
+
+require (
+	github.com/caarlos0/env/v11 v11.3.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	go.opentelemetry.io/otel v1.46.0 // indirect
+)
+
+replace github.com/bpurdy1/golang-packages/envparse => ../../envparse
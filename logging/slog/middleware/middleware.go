@@ -0,0 +1,150 @@
+package slogmiddleware
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	sloglogger "github.com/bpurdy1/golang-packages/logging/slog"
+)
+
+type config struct {
+	logger          *slog.Logger
+	slowThreshold   time.Duration
+	requestIDHeader string
+}
+
+type Option func(*config)
+
+// WithLogger sets the logger requests are logged with (default:
+// slog.Default()).
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *config) {
+		c.logger = logger
+	}
+}
+
+// WithSlowThreshold logs requests taking longer than d at warn level with
+// slow=true; zero disables it.
+func WithSlowThreshold(d time.Duration) Option {
+	return func(c *config) {
+		c.slowThreshold = d
+	}
+}
+
+// WithRequestIDHeader sets the header whose value is added to the request
+// logger as request_id (default: "X-Request-Id").
+func WithRequestIDHeader(header string) Option {
+	return func(c *config) {
+		c.requestIDHeader = header
+	}
+}
+
+// Middleware returns HTTP middleware that places a request-scoped logger in
+// the context, retrievable with sloglogger.LoggerFromContext, and logs one
+// access line per request with its status, response size and latency.
+// Server errors are logged at error level and slow requests at warn level.
+//
+// Panics in the handler are recovered and logged with their stack, and the
+// client receives a 500 if nothing was written yet. http.ErrAbortHandler is
+// re-panicked so the server aborts the response as usual.
+//
+//	mux.Handle("/", slogmiddleware.Middleware(slogmiddleware.WithSlowThreshold(time.Second))(handler))
+func Middleware(opts ...Option) func(http.Handler) http.Handler {
+	cfg := &config{
+		logger:          slog.Default(),
+		requestIDHeader: "X-Request-Id",
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logger := cfg.logger.With("method", r.Method, "path", r.URL.Path)
+			if id := r.Header.Get(cfg.requestIDHeader); id != "" {
+				logger = logger.With("request_id", id)
+			}
+			r = r.WithContext(sloglogger.WithContext(r.Context(), logger))
+
+			rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			start := time.Now()
+
+			defer func() {
+				level := slog.LevelInfo
+				attrs := []any{}
+
+				if p := recover(); p != nil {
+					if p == http.ErrAbortHandler {
+						panic(p)
+					}
+					if !rw.written {
+						http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+					}
+					rw.statusCode = http.StatusInternalServerError
+					logger.ErrorContext(r.Context(), "panic recovered",
+						"error", panicError(p), "stack", string(debug.Stack()))
+				}
+
+				latency := time.Since(start)
+				if cfg.slowThreshold > 0 && latency > cfg.slowThreshold {
+					level = slog.LevelWarn
+					attrs = append(attrs, "slow", true)
+				}
+				if rw.statusCode >= http.StatusInternalServerError {
+					level = slog.LevelError
+				}
+
+				attrs = append(attrs,
+					"status", rw.statusCode,
+					"size", rw.size,
+					"latency", latency,
+					"remote_addr", r.RemoteAddr,
+				)
+				logger.Log(r.Context(), level, "request", attrs...)
+			}()
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+func panicError(p any) error {
+	if err, ok := p.(error); ok {
+		return err
+	}
+	return errors.New(fmt.Sprint(p))
+}
+
+// responseWriter wraps http.ResponseWriter to capture the status code and
+// the number of bytes written.
+type responseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	size       int
+	written    bool
+}
+
+func (rw *responseWriter) WriteHeader(code int) {
+	if !rw.written {
+		rw.statusCode = code
+		rw.written = true
+	}
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	rw.written = true
+	n, err := rw.ResponseWriter.Write(b)
+	rw.size += n
+	return n, err
+}
+
+// Unwrap returns the underlying ResponseWriter, enabling http.ResponseController
+// and interface assertions (e.g. http.Flusher) to work through the wrapper.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
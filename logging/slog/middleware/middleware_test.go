@@ -0,0 +1,67 @@
+package slogmiddleware
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	sloglogger "github.com/bpurdy1/golang-packages/logging/slog"
+	"github.com/bpurdy1/golang-packages/logging/slog/slogtest"
+)
+
+func serve(h http.Handler, opts ...Option) (*httptest.ResponseRecorder, *slogtest.CaptureHandler) {
+	capture := slogtest.NewCaptureHandler()
+	opts = append([]Option{WithLogger(slog.New(capture))}, opts...)
+
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	req.Header.Set("X-Request-Id", "req-1")
+	rec := httptest.NewRecorder()
+	Middleware(opts...)(h).ServeHTTP(rec, req)
+	return rec, capture
+}
+
+func TestMiddleware_AccessLine(t *testing.T) {
+	_, capture := serve(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sloglogger.LoggerFromContext(r.Context()).Info("loading orders")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("hello"))
+	}))
+
+	capture.AssertLogged(t, slog.LevelInfo, "loading orders", "request_id", "req-1", "path", "/orders")
+	capture.AssertLogged(t, slog.LevelInfo, "request",
+		"method", "GET", "path", "/orders", "request_id", "req-1", "status", 201, "size", 5)
+
+	r, _ := capture.Find(slog.LevelInfo, "request")
+	if _, ok := r.Attrs["latency"]; !ok {
+		t.Error("expected latency attribute")
+	}
+}
+
+func TestMiddleware_Panic(t *testing.T) {
+	rec, capture := serve(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	r, ok := capture.Find(slog.LevelError, "panic recovered")
+	if !ok {
+		t.Fatal("expected panic to be logged")
+	}
+	if !strings.Contains(r.Attrs["stack"].String(), "middleware_test.go") {
+		t.Errorf("expected stack trace, got %q", r.Attrs["stack"])
+	}
+	capture.AssertLogged(t, slog.LevelError, "request", "status", 500)
+}
+
+func TestMiddleware_SlowRequest(t *testing.T) {
+	_, capture := serve(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+	}), WithSlowThreshold(time.Millisecond))
+
+	capture.AssertLogged(t, slog.LevelWarn, "request", "slow", true, "status", 200)
+}
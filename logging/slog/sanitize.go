@@ -0,0 +1,238 @@
+package sloglogger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"unicode/utf8"
+)
+
+// SanitizeOptions configures a SanitizeHandler. The zero value only removes
+// duplicate keys.
+type SanitizeOptions struct {
+	// FlattenGroups replaces groups with dotted keys ("http.status") instead
+	// of passing nested groups to the wrapped handler.
+	FlattenGroups bool
+	// MaxAttrs caps the number of attributes per record. Extra attributes are
+	// dropped and counted in an "attrs_truncated" attribute. Zero means no
+	// limit.
+	MaxAttrs int
+	// MaxValueLen caps the length in bytes of string and error values. Longer
+	// values are cut and suffixed with "...[truncated N bytes]". Zero means no
+	// limit.
+	MaxValueLen int
+}
+
+// SanitizeHandler cleans up records before they reach the wrapped handler:
+// repeated keys are collapsed so the last value wins (slog.JSONHandler would
+// otherwise emit duplicate JSON keys), and attribute count and value size are
+// bounded so malformed or enormous records don't break log pipelines.
+//
+// Attributes and groups added with With and WithGroup are held by the
+// SanitizeHandler and passed to the wrapped handler on each record, so they
+// can be deduplicated against the record's own attributes.
+type SanitizeHandler struct {
+	next  slog.Handler
+	opts  SanitizeOptions
+	steps []sanitizeStep
+}
+
+// sanitizeStep is either a group opened by WithGroup or attributes added by
+// WithAttrs, in call order.
+type sanitizeStep struct {
+	group string
+	attrs []slog.Attr
+}
+
+// NewSanitizeHandler wraps next. A nil opts is the same as the zero value.
+func NewSanitizeHandler(next slog.Handler, opts *SanitizeOptions) *SanitizeHandler {
+	h := &SanitizeHandler{next: next}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+func (h *SanitizeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *SanitizeHandler) Handle(ctx context.Context, r slog.Record) error {
+	recordAttrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		recordAttrs = append(recordAttrs, a)
+		return true
+	})
+
+	attrs := h.build(0, recordAttrs)
+	if h.opts.FlattenGroups {
+		attrs = dedupAttrs(flattenAttrs(nil, "", attrs))
+	}
+
+	dropped := 0
+	if h.opts.MaxAttrs > 0 {
+		attrs, dropped = limitAttrs(attrs, h.opts.MaxAttrs)
+	}
+	if h.opts.MaxValueLen > 0 {
+		attrs = truncateAttrs(attrs, h.opts.MaxValueLen)
+	}
+	if dropped > 0 {
+		attrs = append(attrs, slog.Int("attrs_truncated", dropped))
+	}
+
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	nr.AddAttrs(attrs...)
+	return h.next.Handle(ctx, nr)
+}
+
+// build nests the attributes of steps[i:] and the record's attributes into
+// their groups, deduplicating each level.
+func (h *SanitizeHandler) build(i int, recordAttrs []slog.Attr) []slog.Attr {
+	var attrs []slog.Attr
+	for ; i < len(h.steps); i++ {
+		s := h.steps[i]
+		if s.group == "" {
+			attrs = append(attrs, s.attrs...)
+			continue
+		}
+		if inner := h.build(i+1, recordAttrs); len(inner) > 0 {
+			attrs = append(attrs, slog.Attr{Key: s.group, Value: slog.GroupValue(inner...)})
+		}
+		return dedupAttrs(attrs)
+	}
+	return dedupAttrs(append(attrs, recordAttrs...))
+}
+
+func (h *SanitizeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.withStep(sanitizeStep{attrs: attrs})
+}
+
+func (h *SanitizeHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.withStep(sanitizeStep{group: name})
+}
+
+func (h *SanitizeHandler) withStep(s sanitizeStep) *SanitizeHandler {
+	nh := *h
+	nh.steps = append(h.steps[:len(h.steps):len(h.steps)], s)
+	return &nh
+}
+
+// dedupAttrs resolves values, inlines groups with empty keys, drops empty
+// attributes and keeps the last value of each key at the position of its
+// first occurrence. Groups with the same key are merged.
+func dedupAttrs(attrs []slog.Attr) []slog.Attr {
+	out := make([]slog.Attr, 0, len(attrs))
+	index := make(map[string]int, len(attrs))
+	for _, a := range attrs {
+		a.Value = a.Value.Resolve()
+		if a.Value.Kind() == slog.KindGroup {
+			group := dedupAttrs(a.Value.Group())
+			if a.Key == "" {
+				out = mergeAttrs(out, index, group...)
+				continue
+			}
+			if len(group) == 0 {
+				continue
+			}
+			a.Value = slog.GroupValue(group...)
+		}
+		if a.Equal(slog.Attr{}) {
+			continue
+		}
+		out = mergeAttrs(out, index, a)
+	}
+	return out
+}
+
+func mergeAttrs(out []slog.Attr, index map[string]int, attrs ...slog.Attr) []slog.Attr {
+	for _, a := range attrs {
+		i, ok := index[a.Key]
+		if !ok {
+			index[a.Key] = len(out)
+			out = append(out, a)
+			continue
+		}
+		prev := out[i]
+		if prev.Value.Kind() == slog.KindGroup && a.Value.Kind() == slog.KindGroup {
+			a.Value = slog.GroupValue(dedupAttrs(slices.Concat(prev.Value.Group(), a.Value.Group()))...)
+		}
+		out[i] = a
+	}
+	return out
+}
+
+func flattenAttrs(dst []slog.Attr, prefix string, attrs []slog.Attr) []slog.Attr {
+	for _, a := range attrs {
+		if a.Value.Kind() == slog.KindGroup {
+			dst = flattenAttrs(dst, prefix+a.Key+".", a.Value.Group())
+			continue
+		}
+		a.Key = prefix + a.Key
+		dst = append(dst, a)
+	}
+	return dst
+}
+
+// limitAttrs keeps the first limit leaf attributes, returning how many were
+// dropped.
+func limitAttrs(attrs []slog.Attr, limit int) ([]slog.Attr, int) {
+	dropped := 0
+	return limitGroup(attrs, &limit, &dropped), dropped
+}
+
+func limitGroup(attrs []slog.Attr, remaining, dropped *int) []slog.Attr {
+	out := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		if a.Value.Kind() == slog.KindGroup {
+			if group := limitGroup(a.Value.Group(), remaining, dropped); len(group) > 0 {
+				out = append(out, slog.Attr{Key: a.Key, Value: slog.GroupValue(group...)})
+			}
+			continue
+		}
+		if *remaining == 0 {
+			*dropped++
+			continue
+		}
+		*remaining--
+		out = append(out, a)
+	}
+	return out
+}
+
+func truncateAttrs(attrs []slog.Attr, maxLen int) []slog.Attr {
+	out := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		switch a.Value.Kind() {
+		case slog.KindGroup:
+			a.Value = slog.GroupValue(truncateAttrs(a.Value.Group(), maxLen)...)
+		case slog.KindString:
+			if s := a.Value.String(); len(s) > maxLen {
+				a.Value = slog.StringValue(truncate(s, maxLen))
+			}
+		case slog.KindAny:
+			if err, ok := a.Value.Any().(error); ok {
+				if s := err.Error(); len(s) > maxLen {
+					a.Value = slog.StringValue(truncate(s, maxLen))
+				}
+			}
+		}
+		out[i] = a
+	}
+	return out
+}
+
+func truncate(s string, maxLen int) string {
+	cut := maxLen
+	// Don't split a multi-byte character.
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s...[truncated %d bytes]", s[:cut], len(s)-cut)
+}
@@ -0,0 +1,95 @@
+package sloglogger
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func sanitizedJSON(opts *SanitizeOptions, log func(*slog.Logger)) string {
+	var buf bytes.Buffer
+	h := slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	log(slog.New(NewSanitizeHandler(h, opts)))
+	return strings.TrimSpace(buf.String())
+}
+
+func TestSanitizeHandler(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *SanitizeOptions
+		log  func(*slog.Logger)
+		want string
+	}{
+		{
+			name: "dedup",
+			log: func(l *slog.Logger) {
+				l.With("user", 1, "env", "prod").Info("msg", "user", 2, "user", 3)
+			},
+			want: `{"msg":"msg","user":3,"env":"prod"}`,
+		},
+		{
+			name: "groups preserved and merged",
+			log: func(l *slog.Logger) {
+				l.With(slog.Group("http", "method", "GET")).
+					WithGroup("req").With("id", "a").
+					Info("msg", "id", "b", slog.Group("", "size", 10))
+			},
+			want: `{"msg":"msg","http":{"method":"GET"},"req":{"id":"b","size":10}}`,
+		},
+		{
+			name: "same group key merged",
+			log: func(l *slog.Logger) {
+				l.Info("msg", slog.Group("http", "method", "GET", "status", 500), slog.Group("http", "status", 200))
+			},
+			want: `{"msg":"msg","http":{"method":"GET","status":200}}`,
+		},
+		{
+			name: "empty group omitted",
+			log: func(l *slog.Logger) {
+				l.With("a", 1).WithGroup("empty").Info("msg")
+			},
+			want: `{"msg":"msg","a":1}`,
+		},
+		{
+			name: "flatten",
+			opts: &SanitizeOptions{FlattenGroups: true},
+			log: func(l *slog.Logger) {
+				l.With("http.status", 500).WithGroup("http").Info("msg", "status", 200, "method", "GET")
+			},
+			want: `{"msg":"msg","http.status":200,"http.method":"GET"}`,
+		},
+		{
+			name: "max attrs",
+			opts: &SanitizeOptions{MaxAttrs: 2},
+			log: func(l *slog.Logger) {
+				l.Info("msg", "a", 1, slog.Group("g", "b", 2, "c", 3), "d", 4)
+			},
+			want: `{"msg":"msg","a":1,"g":{"b":2},"attrs_truncated":2}`,
+		},
+		{
+			name: "max value len",
+			opts: &SanitizeOptions{MaxValueLen: 5},
+			log: func(l *slog.Logger) {
+				l.Info("msg", "body", "0123456789", "err", errors.New("boom, boom"), "name", "abcdé")
+			},
+			want: `{"msg":"msg","body":"01234...[truncated 5 bytes]","err":"boom,...[truncated 5 bytes]","name":"abcd...[truncated 2 bytes]"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizedJSON(tt.opts, tt.log); got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,7 +6,7 @@ import (
 	"log/slog"
 	"os"
 
-	"github.com/caarlos0/env/v11"
+	"github.com/bpurdy1/golang-packages/envparse"
 )
 
 // Config represents the settings populated by caarlos0/env
@@ -14,6 +14,12 @@ type Config struct {
 	LogLevel  string `env:"LOG_LEVEL" envDefault:"info"`
 	JSON      bool   `env:"LOG_JSON" envDefault:"false"`
 	AddSource bool   `env:"LOG_SOURCE" envDefault:"false"`
+	// Sanitize installs a SanitizeHandler, which also drops duplicate keys and
+	// empty groups. Setting FlattenGroups, MaxAttrs or MaxValueLen implies it.
+	Sanitize      bool `env:"LOG_SANITIZE" envDefault:"false"`
+	FlattenGroups bool `env:"LOG_FLATTEN_GROUPS" envDefault:"false"`
+	MaxAttrs      int  `env:"LOG_MAX_ATTRS" envDefault:"0"`
+	MaxValueLen   int  `env:"LOG_MAX_VALUE_LEN" envDefault:"0"`
 }
 
 type Option func(*Config)
@@ -36,9 +42,32 @@ func WithSource(addSource bool) Option {
 	}
 }
 
+// WithLimits bounds the number of attributes per record and the size of string
+// values; zero disables a limit.
+func WithLimits(maxAttrs, maxValueLen int) Option {
+	return func(c *Config) {
+		c.MaxAttrs = maxAttrs
+		c.MaxValueLen = maxValueLen
+	}
+}
+
+// WithSanitize installs a SanitizeHandler without limits, to drop duplicate
+// keys and empty groups.
+func WithSanitize(sanitize bool) Option {
+	return func(c *Config) {
+		c.Sanitize = sanitize
+	}
+}
+
+func WithFlattenGroups(flatten bool) Option {
+	return func(c *Config) {
+		c.FlattenGroups = flatten
+	}
+}
+
 func NewConfig() (*Config, error) {
 	var cfg Config
-	if err := env.Parse(&cfg); err != nil {
+	if err := envparse.Parse(&cfg); err != nil {
 		return nil, err
 	}
 	return &cfg, nil
@@ -78,7 +107,15 @@ func NewLogger(cfg *Config) *slog.Logger {
 		handler = slog.NewTextHandler(os.Stderr, opts)
 	}
 
-	return slog.New(handler)
+	if cfg.Sanitize || cfg.FlattenGroups || cfg.MaxAttrs > 0 || cfg.MaxValueLen > 0 {
+		handler = NewSanitizeHandler(handler, &SanitizeOptions{
+			FlattenGroups: cfg.FlattenGroups,
+			MaxAttrs:      cfg.MaxAttrs,
+			MaxValueLen:   cfg.MaxValueLen,
+		})
+	}
+
+	return slog.New(NewTraceHandler(handler))
 }
 
 func parseLevel(level string) slog.Level {
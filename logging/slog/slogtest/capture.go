@@ -0,0 +1,185 @@
+// Package slogtest provides a slog.Handler that records log output for tests.
+package slogtest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// CapturedRecord is a log record recorded by a CaptureHandler. Attributes from
+// groups are flattened into dotted keys (e.g. "http.status").
+type CapturedRecord struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   map[string]slog.Value
+}
+
+type captureStore struct {
+	mu      sync.Mutex
+	records []CapturedRecord
+}
+
+// CaptureHandler is a slog.Handler that records every record it receives so
+// tests can assert on logging behaviour.
+//
+//	h := slogtest.NewCaptureHandler()
+//	logger := slog.New(h)
+//	doWork(logger)
+//	h.AssertLogged(t, slog.LevelError, "failed", "user_id", 42)
+type CaptureHandler struct {
+	store  *captureStore
+	prefix string
+	attrs  []slog.Attr
+}
+
+// NewCaptureHandler creates an empty CaptureHandler.
+func NewCaptureHandler() *CaptureHandler {
+	return &CaptureHandler{store: &captureStore{}}
+}
+
+func (h *CaptureHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *CaptureHandler) Handle(_ context.Context, r slog.Record) error {
+	attrs := make(map[string]slog.Value)
+	for _, a := range h.attrs {
+		flattenAttr(attrs, "", a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		flattenAttr(attrs, h.prefix, a)
+		return true
+	})
+
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+	h.store.records = append(h.store.records, CapturedRecord{
+		Time:    r.Time,
+		Level:   r.Level,
+		Message: r.Message,
+		Attrs:   attrs,
+	})
+	return nil
+}
+
+func (h *CaptureHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	nh := *h
+	nh.attrs = append([]slog.Attr{}, h.attrs...)
+	for _, a := range attrs {
+		if h.prefix != "" {
+			a.Key = h.prefix + a.Key
+		}
+		nh.attrs = append(nh.attrs, a)
+	}
+	return &nh
+}
+
+func (h *CaptureHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	nh := *h
+	nh.prefix = h.prefix + name + "."
+	return &nh
+}
+
+func flattenAttr(dst map[string]slog.Value, prefix string, a slog.Attr) {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix = prefix + a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			flattenAttr(dst, groupPrefix, ga)
+		}
+		return
+	}
+	if a.Key == "" {
+		return
+	}
+	dst[prefix+a.Key] = v
+}
+
+// Records returns a copy of the captured records in the order they were logged.
+func (h *CaptureHandler) Records() []CapturedRecord {
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+	return append([]CapturedRecord(nil), h.store.records...)
+}
+
+// Reset discards all captured records.
+func (h *CaptureHandler) Reset() {
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+	h.store.records = nil
+}
+
+// Find returns the first record at level whose message contains msgContains
+// and which carries every attribute in attrs. attrs accepts the same
+// key/value pairs and slog.Attr values as slog.Logger.Info.
+func (h *CaptureHandler) Find(level slog.Level, msgContains string, attrs ...any) (CapturedRecord, bool) {
+	want := argsToAttrs(attrs)
+	for _, r := range h.Records() {
+		if r.Level != level || !strings.Contains(r.Message, msgContains) {
+			continue
+		}
+		if hasAttrs(r, want) {
+			return r, true
+		}
+	}
+	return CapturedRecord{}, false
+}
+
+// AssertLogged fails the test unless a matching record was captured. See Find
+// for the matching rules.
+func (h *CaptureHandler) AssertLogged(t testing.TB, level slog.Level, msgContains string, attrs ...any) {
+	t.Helper()
+	if _, ok := h.Find(level, msgContains, attrs...); !ok {
+		t.Errorf("expected %s record containing %q with attrs %v, captured:\n%s",
+			level, msgContains, attrs, h.dump())
+	}
+}
+
+// AssertNotLogged fails the test if a matching record was captured.
+func (h *CaptureHandler) AssertNotLogged(t testing.TB, level slog.Level, msgContains string, attrs ...any) {
+	t.Helper()
+	if r, ok := h.Find(level, msgContains, attrs...); ok {
+		t.Errorf("unexpected %s record %q", r.Level, r.Message)
+	}
+}
+
+func argsToAttrs(args []any) []slog.Attr {
+	var r slog.Record
+	r.Add(args...)
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return attrs
+}
+
+func hasAttrs(r CapturedRecord, want []slog.Attr) bool {
+	for _, a := range want {
+		got, ok := r.Attrs[a.Key]
+		if !ok || !got.Equal(a.Value.Resolve()) {
+			return false
+		}
+	}
+	return true
+}
+
+func (h *CaptureHandler) dump() string {
+	var sb strings.Builder
+	for _, r := range h.Records() {
+		sb.WriteString(fmt.Sprintf("  %s %q %v\n", r.Level, r.Message, r.Attrs))
+	}
+	return sb.String()
+}
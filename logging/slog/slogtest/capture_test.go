@@ -0,0 +1,83 @@
+package slogtest
+
+import (
+	"log/slog"
+	"testing"
+)
+
+type fakeTB struct {
+	testing.TB
+	failed bool
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Errorf(string, ...any) { f.failed = true }
+
+func TestCaptureHandler_Records(t *testing.T) {
+	h := NewCaptureHandler()
+	logger := slog.New(h)
+
+	logger.Info("user created", "user_id", 42, "admin", true)
+	logger.Debug("debug detail")
+
+	records := h.Records()
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	if records[0].Message != "user created" {
+		t.Errorf("Message = %q, want %q", records[0].Message, "user created")
+	}
+	if records[0].Attrs["user_id"].Int64() != 42 {
+		t.Errorf("user_id = %v, want 42", records[0].Attrs["user_id"])
+	}
+	if records[1].Level != slog.LevelDebug {
+		t.Errorf("Level = %v, want debug", records[1].Level)
+	}
+}
+
+func TestCaptureHandler_AssertLogged(t *testing.T) {
+	h := NewCaptureHandler()
+	logger := slog.New(h).With("service", "billing")
+
+	logger.Error("charge failed", "amount", 100, slog.String("currency", "usd"))
+
+	h.AssertLogged(t, slog.LevelError, "charge", "amount", 100)
+	h.AssertLogged(t, slog.LevelError, "failed", "service", "billing", slog.String("currency", "usd"))
+	h.AssertNotLogged(t, slog.LevelInfo, "charge")
+	h.AssertNotLogged(t, slog.LevelError, "charge", "amount", 200)
+}
+
+func TestCaptureHandler_AssertLoggedFails(t *testing.T) {
+	h := NewCaptureHandler()
+	slog.New(h).Info("hello")
+
+	ft := &fakeTB{}
+	h.AssertLogged(ft, slog.LevelWarn, "hello")
+	if !ft.failed {
+		t.Error("expected AssertLogged to fail for wrong level")
+	}
+}
+
+func TestCaptureHandler_Groups(t *testing.T) {
+	h := NewCaptureHandler()
+	logger := slog.New(h).WithGroup("http").With("method", "GET")
+
+	logger.Info("request", slog.Group("response", "status", 200))
+
+	h.AssertLogged(t, slog.LevelInfo, "request", "http.method", "GET", "http.response.status", 200)
+}
+
+func TestCaptureHandler_Reset(t *testing.T) {
+	h := NewCaptureHandler()
+	logger := slog.New(h)
+
+	logger.Info("first")
+	h.Reset()
+	logger.Info("second")
+
+	records := h.Records()
+	if len(records) != 1 || records[0].Message != "second" {
+		t.Errorf("expected only the record after Reset, got %+v", records)
+	}
+}
@@ -0,0 +1,99 @@
+package sloglogger
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceHandler adds trace_id and span_id attributes from the OpenTelemetry
+// span in the record's context, so logs can be correlated with traces. Records
+// logged without an active span are passed through unchanged. Use the
+// *Context logging methods (e.g. logger.InfoContext) to supply the context.
+//
+// The trace attributes are always top-level, even on a logger with groups, so
+// log pipelines find them in the same place.
+type TraceHandler struct {
+	next slog.Handler
+	// steps holds the groups and attributes added since the first WithGroup;
+	// they are applied in Handle, after the trace attributes.
+	steps []traceStep
+}
+
+type traceStep struct {
+	group string
+	attrs []slog.Attr
+}
+
+// NewTraceHandler wraps next with trace enrichment. NewLogger installs it by
+// default.
+func NewTraceHandler(next slog.Handler) *TraceHandler {
+	return &TraceHandler{next: next}
+}
+
+func (h *TraceHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *TraceHandler) Handle(ctx context.Context, r slog.Record) error {
+	sc := trace.SpanContextFromContext(ctx)
+	if len(h.steps) == 0 {
+		if sc.IsValid() {
+			r = r.Clone()
+			r.AddAttrs(traceAttrs(sc)...)
+		}
+		return h.next.Handle(ctx, r)
+	}
+
+	// Nest the record's attributes under the groups ourselves so the trace
+	// attributes can be added outside them.
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	for i := len(h.steps) - 1; i >= 0; i-- {
+		step := h.steps[i]
+		if step.group != "" {
+			attrs = []slog.Attr{{Key: step.group, Value: slog.GroupValue(attrs...)}}
+			continue
+		}
+		attrs = append(append([]slog.Attr{}, step.attrs...), attrs...)
+	}
+
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	if sc.IsValid() {
+		nr.AddAttrs(traceAttrs(sc)...)
+	}
+	nr.AddAttrs(attrs...)
+	return h.next.Handle(ctx, nr)
+}
+
+func traceAttrs(sc trace.SpanContext) []slog.Attr {
+	return []slog.Attr{
+		slog.String("trace_id", sc.TraceID().String()),
+		slog.String("span_id", sc.SpanID().String()),
+	}
+}
+
+func (h *TraceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	if len(h.steps) == 0 {
+		return &TraceHandler{next: h.next.WithAttrs(attrs)}
+	}
+	return &TraceHandler{next: h.next, steps: h.withStep(traceStep{attrs: attrs})}
+}
+
+func (h *TraceHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &TraceHandler{next: h.next, steps: h.withStep(traceStep{group: name})}
+}
+
+func (h *TraceHandler) withStep(step traceStep) []traceStep {
+	return append(append(make([]traceStep, 0, len(h.steps)+1), h.steps...), step)
+}
@@ -0,0 +1,67 @@
+package sloglogger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/bpurdy1/golang-packages/logging/slog/slogtest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func spanContext() context.Context {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	return trace.ContextWithSpanContext(context.Background(), sc)
+}
+
+func TestTraceHandler(t *testing.T) {
+	h := slogtest.NewCaptureHandler()
+	logger := slog.New(NewTraceHandler(h)).With("service", "billing")
+
+	logger.InfoContext(spanContext(), "traced")
+	logger.Info("untraced")
+
+	h.AssertLogged(t, slog.LevelInfo, "traced",
+		"trace_id", "4bf92f3577b34da6a3ce929d0e0e4736",
+		"span_id", "00f067aa0ba902b7",
+		"service", "billing")
+
+	r, ok := h.Find(slog.LevelInfo, "untraced")
+	if !ok {
+		t.Fatal("expected untraced record")
+	}
+	if _, ok := r.Attrs["trace_id"]; ok {
+		t.Error("expected no trace_id without an active span")
+	}
+}
+
+func TestTraceHandler_Groups(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewTraceHandler(slog.NewJSONHandler(&buf, nil))).
+		With("service", "billing").
+		WithGroup("req").
+		With("id", 7)
+
+	logger.InfoContext(spanContext(), "traced", "status", 200)
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	if got["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" || got["span_id"] != "00f067aa0ba902b7" {
+		t.Errorf("expected top-level trace attributes, got %s", buf.String())
+	}
+	req, _ := got["req"].(map[string]any)
+	if got["service"] != "billing" || req["id"] != float64(7) || req["status"] != float64(200) {
+		t.Errorf("expected attributes under their groups, got %s", buf.String())
+	}
+	if _, ok := req["trace_id"]; ok {
+		t.Errorf("expected trace_id outside the group, got %s", buf.String())
+	}
+}
@@ -0,0 +1,239 @@
+package zerologlogger
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+var ErrAsyncWriterClosed = errors.New("zerologlogger: async writer closed")
+
+// OverflowPolicy decides what an AsyncWriter does when its buffer is full.
+type OverflowPolicy int
+
+const (
+	// Block makes the logging call wait until there is room in the buffer.
+	Block OverflowPolicy = iota
+	// DropOldest discards the oldest buffered record to make room.
+	DropOldest
+)
+
+type asyncEntry struct {
+	level zerolog.Level
+	p     []byte
+	seq   uint64
+}
+
+// AsyncWriter buffers log records in a bounded queue and writes them to the
+// underlying writer from a background goroutine. Fatal and panic records are
+// written synchronously after draining the queue, so they are never lost. If
+// the writer has not been started yet, the queue and the record are written
+// to stderr instead.
+type AsyncWriter struct {
+	size   int
+	policy OverflowPolicy
+
+	mu      sync.Mutex
+	cond    *sync.Cond // broadcast on every change to queue, written, started or closed
+	queue   []asyncEntry
+	seq     uint64 // sequence number of the last queued record
+	written uint64 // sequence number of the last record written
+	out     io.Writer
+	started bool
+	closed  bool
+	done    chan struct{}
+
+	writeMu  sync.Mutex // serialises writes to out and fallback
+	fallback io.Writer  // receives fatal and panic records before Start
+	dropped  atomic.Uint64
+}
+
+// NewAsyncWriter creates an AsyncWriter buffering up to size records. Records
+// are queued but not written until it is started, either by passing it to
+// NewLogger with WithAsync or by calling Start.
+func NewAsyncWriter(size int, policy OverflowPolicy) *AsyncWriter {
+	if size < 1 {
+		size = 1
+	}
+	a := &AsyncWriter{
+		size:     size,
+		policy:   policy,
+		queue:    make([]asyncEntry, 0, size),
+		done:     make(chan struct{}),
+		fallback: os.Stderr,
+	}
+	a.cond = sync.NewCond(&a.mu)
+	return a
+}
+
+// Start begins writing queued records to out. Later calls are ignored.
+func (a *AsyncWriter) Start(out io.Writer) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.started {
+		return
+	}
+	a.out = out
+	a.started = true
+	go a.run()
+}
+
+func (a *AsyncWriter) run() {
+	defer close(a.done)
+	for {
+		a.mu.Lock()
+		for len(a.queue) == 0 && !a.closed {
+			a.cond.Wait()
+		}
+		if len(a.queue) == 0 {
+			a.mu.Unlock()
+			return
+		}
+		e := a.queue[0]
+		a.queue[0] = asyncEntry{}
+		a.queue = a.queue[1:]
+		a.cond.Broadcast()
+		a.mu.Unlock()
+
+		a.writeMu.Lock()
+		_, _ = a.write(e.level, e.p)
+		a.writeMu.Unlock()
+
+		a.mu.Lock()
+		a.written = e.seq
+		a.cond.Broadcast()
+		a.mu.Unlock()
+	}
+}
+
+func (a *AsyncWriter) write(level zerolog.Level, p []byte) (int, error) {
+	if lw, ok := a.out.(zerolog.LevelWriter); ok {
+		return lw.WriteLevel(level, p)
+	}
+	return a.out.Write(p)
+}
+
+// Write implements io.Writer.
+func (a *AsyncWriter) Write(p []byte) (int, error) {
+	return a.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel implements zerolog.LevelWriter.
+func (a *AsyncWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level == zerolog.FatalLevel || level == zerolog.PanicLevel {
+		return a.writeNow(level, p)
+	}
+
+	// zerolog reuses p after Write returns.
+	buf := make([]byte, len(p))
+	copy(buf, p)
+
+	if err := a.enqueue(asyncEntry{level: level, p: buf}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// writeNow drains the queue and writes p in place, since the process is
+// about to exit or unwind. Before Start nothing would drain the queue, so it
+// goes to the fallback writer rather than waiting.
+func (a *AsyncWriter) writeNow(level zerolog.Level, p []byte) (int, error) {
+	a.mu.Lock()
+	if !a.started && !a.closed {
+		queued := a.queue
+		a.queue = make([]asyncEntry, 0, a.size)
+		a.written = a.seq
+		a.cond.Broadcast()
+		a.mu.Unlock()
+
+		a.writeMu.Lock()
+		defer a.writeMu.Unlock()
+		for _, e := range queued {
+			_, _ = a.fallback.Write(e.p)
+		}
+		return a.fallback.Write(p)
+	}
+	a.mu.Unlock()
+
+	if err := a.Flush(); err != nil {
+		return 0, err
+	}
+	a.writeMu.Lock()
+	defer a.writeMu.Unlock()
+	return a.write(level, p)
+}
+
+func (a *AsyncWriter) enqueue(e asyncEntry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for {
+		if a.closed {
+			return ErrAsyncWriterClosed
+		}
+		if len(a.queue) < a.size {
+			break
+		}
+		if a.policy == DropOldest {
+			a.queue[0] = asyncEntry{}
+			a.queue = a.queue[1:]
+			a.dropped.Add(1)
+			break
+		}
+		a.cond.Wait()
+	}
+
+	a.seq++
+	e.seq = a.seq
+	a.queue = append(a.queue, e)
+	a.cond.Broadcast()
+	return nil
+}
+
+// Flush blocks until every record buffered before the call has been written
+// or dropped. It waits for the writer to be started.
+func (a *AsyncWriter) Flush() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return ErrAsyncWriterClosed
+	}
+	// A dropped record is always followed by a newer one, so written reaches
+	// target once everything queued so far is handled.
+	target := a.seq
+	for a.written < target {
+		if a.closed && !a.started {
+			return ErrAsyncWriterClosed
+		}
+		a.cond.Wait()
+	}
+	return nil
+}
+
+// Close writes any buffered records and stops the background goroutine.
+// Records buffered by a writer that was never started are discarded. Writes
+// after Close return ErrAsyncWriterClosed.
+func (a *AsyncWriter) Close() error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+	a.closed = true
+	started := a.started
+	a.cond.Broadcast()
+	a.mu.Unlock()
+
+	if started {
+		<-a.done
+	}
+	return nil
+}
+
+// Dropped returns the number of records discarded by the DropOldest policy.
+func (a *AsyncWriter) Dropped() uint64 {
+	return a.dropped.Load()
+}
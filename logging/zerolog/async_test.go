@@ -0,0 +1,202 @@
+package zerologlogger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func newStartedAsyncWriter(out io.Writer, size int, policy OverflowPolicy) *AsyncWriter {
+	aw := NewAsyncWriter(size, policy)
+	aw.Start(out)
+	return aw
+}
+
+// blockingWriter blocks every Write until release is closed.
+type blockingWriter struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	release chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *blockingWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestWithAsync_WritesOnClose(t *testing.T) {
+	var buf bytes.Buffer
+	aw := NewAsyncWriter(16, Block)
+	logger := NewLogger(WithWriter(&buf), WithLevel("info"), WithAsync(aw))
+
+	for i := 0; i < 10; i++ {
+		logger.Info().Int("i", i).Msg("async")
+	}
+	if err := aw.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+
+	if got := strings.Count(buf.String(), `"message":"async"`); got != 10 {
+		t.Errorf("expected 10 records, got %d", got)
+	}
+	if aw.Dropped() != 0 {
+		t.Errorf("Block policy should not drop, dropped %d", aw.Dropped())
+	}
+}
+
+func TestAsyncWriter_Flush(t *testing.T) {
+	var buf bytes.Buffer
+	aw := newStartedAsyncWriter(&buf, 4, Block)
+	defer aw.Close()
+
+	if _, err := aw.Write([]byte("one\n")); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	if err := aw.Flush(); err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+	if buf.String() != "one\n" {
+		t.Errorf("expected flushed output, got %q", buf.String())
+	}
+}
+
+func TestAsyncWriter_DropOldest(t *testing.T) {
+	w := &blockingWriter{release: make(chan struct{})}
+	aw := newStartedAsyncWriter(w, 2, DropOldest)
+
+	for i := 0; i < 10; i++ {
+		if _, err := aw.Write([]byte{byte('0' + i)}); err != nil {
+			t.Fatalf("unexpected write error: %v", err)
+		}
+	}
+	close(w.release)
+	if err := aw.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+
+	if aw.Dropped() == 0 {
+		t.Fatal("expected records to be dropped")
+	}
+	out := w.String()
+	if !strings.HasSuffix(out, "9") {
+		t.Errorf("expected newest record to be kept, got %q", out)
+	}
+	if uint64(len(out))+aw.Dropped() != 10 {
+		t.Errorf("written (%d) + dropped (%d) should equal 10", len(out), aw.Dropped())
+	}
+}
+
+func TestAsyncWriter_FatalLevelIsSynchronous(t *testing.T) {
+	var buf bytes.Buffer
+	aw := newStartedAsyncWriter(&buf, 4, Block)
+	defer aw.Close()
+
+	_, _ = aw.WriteLevel(zerolog.InfoLevel, []byte("before\n"))
+	_, _ = aw.WriteLevel(zerolog.FatalLevel, []byte("fatal\n"))
+
+	// No Flush: the fatal write must already have drained the queue.
+	if buf.String() != "before\nfatal\n" {
+		t.Errorf("expected ordered synchronous output, got %q", buf.String())
+	}
+}
+
+func TestAsyncWriter_WriteAfterClose(t *testing.T) {
+	aw := newStartedAsyncWriter(&bytes.Buffer{}, 1, Block)
+	if err := aw.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+	if _, err := aw.Write([]byte("late")); !errors.Is(err, ErrAsyncWriterClosed) {
+		t.Errorf("expected ErrAsyncWriterClosed, got %v", err)
+	}
+}
+
+func TestAsyncWriter_FlushWaitsUnderDropOldest(t *testing.T) {
+	w := &blockingWriter{release: make(chan struct{})}
+	aw := newStartedAsyncWriter(w, 1, DropOldest)
+	defer aw.Close()
+
+	_, _ = aw.Write([]byte("a"))
+	flushed := make(chan struct{})
+	go func() {
+		_ = aw.Flush()
+		close(flushed)
+	}()
+	// Overflow the queue while the flush is pending.
+	for i := 0; i < 10; i++ {
+		_, _ = aw.Write([]byte("b"))
+	}
+
+	select {
+	case <-flushed:
+		t.Fatal("Flush returned before buffered records were written")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(w.release)
+	<-flushed
+	if w.String() == "" {
+		t.Error("expected records written before Flush returned")
+	}
+}
+
+func TestAsyncWriter_WriteBeforeStart(t *testing.T) {
+	var buf bytes.Buffer
+	aw := NewAsyncWriter(4, Block)
+	defer aw.Close()
+
+	if _, err := aw.Write([]byte("early\n")); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	aw.Start(&buf)
+	if err := aw.Flush(); err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+	if buf.String() != "early\n" {
+		t.Errorf("expected record queued before Start to be written, got %q", buf.String())
+	}
+}
+
+func TestAsyncWriter_FatalBeforeStart(t *testing.T) {
+	var fallback bytes.Buffer
+	aw := NewAsyncWriter(4, Block)
+	aw.fallback = &fallback
+	defer aw.Close()
+
+	_, _ = aw.WriteLevel(zerolog.InfoLevel, []byte("before\n"))
+	done := make(chan struct{})
+	go func() {
+		_, _ = aw.WriteLevel(zerolog.FatalLevel, []byte("fatal\n"))
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("fatal write on an unstarted writer blocked")
+	}
+	if fallback.String() != "before\nfatal\n" {
+		t.Errorf("expected queue and fatal record on the fallback, got %q", fallback.String())
+	}
+
+	// The drained records are not written again once started.
+	var buf bytes.Buffer
+	aw.Start(&buf)
+	if err := aw.Flush(); err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing left to write, got %q", buf.String())
+	}
+}
@@ -0,0 +1,171 @@
+package zerologlogger
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+
+	"github.com/rs/zerolog"
+)
+
+type errorConfig struct {
+	maxDepth   int
+	stackDepth int
+}
+
+type ErrorOption func(*errorConfig)
+
+// WithErrorDepth limits how many levels of wrapped errors are serialized
+// (default: 10). Deeper chains are cut and marked with "truncated": true.
+func WithErrorDepth(n int) ErrorOption {
+	return func(c *errorConfig) {
+		c.maxDepth = n
+	}
+}
+
+// WithStackDepth limits how many frames of a stack captured by WithStack are
+// serialized (default: 32); zero omits stacks.
+func WithStackDepth(n int) ErrorOption {
+	return func(c *errorConfig) {
+		c.stackDepth = n
+	}
+}
+
+// WithStructuredErrors makes Event.Err and friends serialize errors with
+// ErrorObject instead of as a flat string. Like the caller options it sets a
+// zerolog global, so it affects every logger.
+//
+//	logger := zerologlogger.NewLogger(zerologlogger.WithStructuredErrors(zerologlogger.WithStackDepth(8)))
+//	logger.Error().Err(err).Msg("charge failed")
+func WithStructuredErrors(opts ...ErrorOption) Option {
+	return func(c *option) {
+		c.ErrorMarshalFunc = func(err error) interface{} {
+			return ErrorObject(err, opts...)
+		}
+	}
+}
+
+// ErrorObject serializes err and the errors it wraps, via %w or errors.Join,
+// as nested objects:
+//
+//	{"message": "charge: card declined", "type": "*fmt.wrapError",
+//	 "cause": {"message": "card declined", "type": "*billing.DeclineError",
+//	           "stack": [{"func": "billing.Charge", "file": "charge.go", "line": 42}]}}
+//
+// Joined errors are listed under "causes". Stacks are included for errors
+// wrapped with WithStack.
+//
+//	logger.Error().Object("error", zerologlogger.ErrorObject(err)).Msg("charge failed")
+func ErrorObject(err error, opts ...ErrorOption) zerolog.LogObjectMarshaler {
+	cfg := &errorConfig{
+		maxDepth:   10,
+		stackDepth: 32,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return errorNode{err: err, cfg: cfg}
+}
+
+type errorNode struct {
+	err   error
+	cfg   *errorConfig
+	depth int
+}
+
+func (n errorNode) MarshalZerologObject(e *zerolog.Event) {
+	if n.err == nil {
+		return
+	}
+
+	// A stackError is transparent: its stack is reported on the error it wraps.
+	err := n.err
+	var pcs []uintptr
+	for {
+		s, ok := err.(*stackError)
+		if !ok {
+			break
+		}
+		if pcs == nil {
+			pcs = s.pcs
+		}
+		err = s.err
+	}
+
+	e.Str("message", err.Error()).Str("type", fmt.Sprintf("%T", err))
+	if pcs != nil && n.cfg.stackDepth > 0 {
+		e.Array("stack", stackFrames{pcs: pcs, depth: n.cfg.stackDepth})
+	}
+
+	var causes []error
+	switch u := err.(type) {
+	case interface{ Unwrap() []error }:
+		causes = u.Unwrap()
+	case interface{ Unwrap() error }:
+		if cause := u.Unwrap(); cause != nil {
+			causes = []error{cause}
+		}
+	}
+	if len(causes) == 0 {
+		return
+	}
+	if n.depth+1 >= n.cfg.maxDepth {
+		e.Bool("truncated", true)
+		return
+	}
+
+	if len(causes) == 1 {
+		e.Object("cause", errorNode{err: causes[0], cfg: n.cfg, depth: n.depth + 1})
+		return
+	}
+	arr := zerolog.Arr()
+	for _, cause := range causes {
+		arr.Object(errorNode{err: cause, cfg: n.cfg, depth: n.depth + 1})
+	}
+	e.Array("causes", arr)
+}
+
+type stackFrames struct {
+	pcs   []uintptr
+	depth int
+}
+
+func (s stackFrames) MarshalZerologArray(a *zerolog.Array) {
+	frames := runtime.CallersFrames(s.pcs)
+	for i := 0; i < s.depth; i++ {
+		f, more := frames.Next()
+		a.Dict(zerolog.Dict().Str("func", f.Function).Str("file", f.File).Int("line", f.Line))
+		if !more {
+			break
+		}
+	}
+}
+
+// stackError records the stack at the point an error was wrapped.
+type stackError struct {
+	err error
+	pcs []uintptr
+}
+
+func (e *stackError) Error() string { return e.err.Error() }
+func (e *stackError) Unwrap() error { return e.err }
+
+// WithStack annotates err with the caller's stack, reported by ErrorObject.
+// It returns nil for a nil err and err itself if its chain already has a
+// stack.
+//
+//	if err := row.Scan(&u.ID); err != nil {
+//		return zerologlogger.WithStack(err)
+//	}
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	var s *stackError
+	if errors.As(err, &s) {
+		return err
+	}
+	var pcs [64]uintptr
+	n := runtime.Callers(2, pcs[:])
+	return &stackError{err: err, pcs: pcs[:n]}
+}
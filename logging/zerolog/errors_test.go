@@ -0,0 +1,99 @@
+package zerologlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func logError(t *testing.T, err error, opts ...ErrorOption) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+	logger.Error().Object("error", ErrorObject(err, opts...)).Msg("failed")
+
+	var out struct {
+		Error map[string]any `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	return out.Error
+}
+
+func TestErrorObject_Chain(t *testing.T) {
+	base := errors.New("card declined")
+	err := fmt.Errorf("charge: %w", errors.Join(base, errors.New("audit failed")))
+
+	got := logError(t, err)
+
+	if got["message"] != err.Error() || got["type"] != "*fmt.wrapError" {
+		t.Errorf("unexpected root: %v", got)
+	}
+	joined := got["cause"].(map[string]any)
+	causes := joined["causes"].([]any)
+	if len(causes) != 2 {
+		t.Fatalf("expected 2 joined causes, got %v", joined)
+	}
+	if causes[0].(map[string]any)["message"] != "card declined" {
+		t.Errorf("unexpected first cause: %v", causes[0])
+	}
+}
+
+func TestErrorObject_Depth(t *testing.T) {
+	err := fmt.Errorf("a: %w", fmt.Errorf("b: %w", errors.New("c")))
+
+	got := logError(t, err, WithErrorDepth(2))
+
+	cause := got["cause"].(map[string]any)
+	if cause["truncated"] != true {
+		t.Errorf("expected chain to be truncated at depth 2, got %v", got)
+	}
+	if _, ok := cause["cause"]; ok {
+		t.Errorf("expected no third level, got %v", got)
+	}
+}
+
+func TestErrorObject_Stack(t *testing.T) {
+	err := fmt.Errorf("query: %w", WithStack(errors.New("no rows")))
+	if WithStack(err) != err {
+		t.Error("expected WithStack not to wrap twice")
+	}
+
+	got := logError(t, err, WithStackDepth(2))
+
+	cause := got["cause"].(map[string]any)
+	if cause["type"] != "*errors.errorString" {
+		t.Errorf("expected stack wrapper to be transparent, got %v", cause)
+	}
+	stack, _ := cause["stack"].([]any)
+	if len(stack) != 2 {
+		t.Fatalf("expected 2 frames, got %v", cause["stack"])
+	}
+	if fn := stack[0].(map[string]any)["func"].(string); !strings.HasSuffix(fn, "TestErrorObject_Stack") {
+		t.Errorf("expected first frame in test, got %q", fn)
+	}
+
+	got = logError(t, err, WithStackDepth(0))
+	if _, ok := got["cause"].(map[string]any)["stack"]; ok {
+		t.Error("expected stack to be omitted")
+	}
+}
+
+func TestWithStructuredErrors(t *testing.T) {
+	orig := zerolog.ErrorMarshalFunc
+	defer func() { zerolog.ErrorMarshalFunc = orig }()
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithWriter(&buf), WithStructuredErrors())
+	logger.Error().Err(fmt.Errorf("outer: %w", errors.New("inner"))).Msg("failed")
+
+	if !strings.Contains(buf.String(), `"cause":{"message":"inner"`) {
+		t.Errorf("expected structured error, got %s", buf.String())
+	}
+}
@@ -3,12 +3,18 @@ module github.com/bpurdy1/golang-packages/logging/zerolog
 go 1.25.6
 
 require (
-	github.com/caarlos0/env/v11 v11.4.0
+	github.com/bpurdy1/golang-packages/envparse v0.0.0-00010101000000-000000000000
 	github.com/rs/zerolog v1.34.0
+	go.opentelemetry.io/otel/trace v1.46.0
 )
 
 require (
+	github.com/caarlos0/env/v11 v11.4.0 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.19 // indirect
+	go.opentelemetry.io/otel v1.46.0 // indirect
 	golang.org/x/sys v0.35.0 // indirect
 )
+
+replace github.com/bpurdy1/golang-packages/envparse => ../../envparse
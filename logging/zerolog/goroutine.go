@@ -0,0 +1,27 @@
+package zerologlogger
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+// CopyContext returns a context for background work started while handling
+// ctx. It keeps ctx's values, including a copy of its logger and fields and
+// the trace span used by TraceHook, but is not cancelled when ctx is, so work
+// outliving a request still logs with its request_id.
+func CopyContext(ctx context.Context) context.Context {
+	// Copy the logger so later UpdateContext calls on the parent's logger
+	// don't race with the goroutine.
+	logger := zerolog.Ctx(ctx).With().Logger()
+	return logger.WithContext(context.WithoutCancel(ctx))
+}
+
+// Go runs fn in a new goroutine with CopyContext(ctx).
+//
+//	zerologlogger.Go(r.Context(), func(ctx context.Context) {
+//		zerologlogger.FromContext(ctx).Info().Msg("sending receipt") // has request_id
+//	})
+func Go(ctx context.Context, fn func(ctx context.Context)) {
+	go fn(CopyContext(ctx))
+}
@@ -0,0 +1,48 @@
+package zerologlogger
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestGo_InheritsLoggerFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(WithWriter(&buf), WithLevel("info"))
+
+	ctx, cancel := context.WithCancel(WithContext(context.Background(), logger))
+	ctx = WithFields(ctx, map[string]any{"request_id": "req-1"})
+
+	done := make(chan error)
+	Go(ctx, func(ctx context.Context) {
+		FromContext(ctx).Info().Msg("background")
+		done <- ctx.Err()
+	})
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Errorf("expected goroutine context to outlive its parent, got %v", err)
+	}
+	output := buf.String()
+	if !strings.Contains(output, `"request_id":"req-1"`) || !strings.Contains(output, "background") {
+		t.Errorf("expected request_id on background log, got %s", output)
+	}
+}
+
+func TestCopyContext_IsolatesLogger(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := WithContext(context.Background(), NewLogger(WithWriter(&buf), WithLevel("info")))
+
+	copied := CopyContext(ctx)
+	FromContext(ctx).UpdateContext(func(c zerolog.Context) zerolog.Context {
+		return c.Str("late", "field")
+	})
+	FromContext(copied).Info().Msg("copied")
+
+	if strings.Contains(buf.String(), "late") {
+		t.Errorf("expected copied logger to be unaffected by later updates, got %s", buf.String())
+	}
+}
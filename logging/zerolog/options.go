@@ -31,3 +31,16 @@ func WithWriter(w io.Writer) Option {
 		c.Writer = w
 	}
 }
+
+// WithAsync routes output through aw, so logging calls don't stall on slow
+// sinks. NewLogger starts aw on the configured writer; keep it to Close it on
+// shutdown and to read its Dropped counter.
+//
+//	aw := zerologlogger.NewAsyncWriter(4096, zerologlogger.DropOldest)
+//	logger := zerologlogger.NewLogger(zerologlogger.WithAsync(aw))
+//	defer aw.Close()
+func WithAsync(aw *AsyncWriter) Option {
+	return func(c *option) {
+		c.Async = aw
+	}
+}
@@ -0,0 +1,26 @@
+package zerologlogger
+
+import (
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceHook adds trace_id and span_id fields from the OpenTelemetry span in
+// the event's context, so logs can be correlated with traces. Events without
+// an active span are left unchanged. Attach the context with Event.Ctx:
+//
+//	logger.Info().Ctx(ctx).Msg("charged card")
+//
+// NewLogger installs the hook by default.
+type TraceHook struct{}
+
+func (TraceHook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	ctx := e.GetCtx()
+	if ctx == nil {
+		return
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		e.Str("trace_id", sc.TraceID().String()).
+			Str("span_id", sc.SpanID().String())
+	}
+}
@@ -0,0 +1,41 @@
+package zerologlogger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceHook(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	var buf bytes.Buffer
+	logger := NewLogger(WithWriter(&buf), WithLevel("info"))
+
+	logger.Info().Ctx(ctx).Msg("traced")
+
+	var m map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("expected valid JSON output, got: %s", buf.String())
+	}
+	if m["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace_id = %v", m["trace_id"])
+	}
+	if m["span_id"] != "00f067aa0ba902b7" {
+		t.Errorf("span_id = %v", m["span_id"])
+	}
+
+	buf.Reset()
+	logger.Info().Msg("untraced")
+	if bytes.Contains(buf.Bytes(), []byte("trace_id")) {
+		t.Errorf("expected no trace_id without a span, got: %s", buf.String())
+	}
+}
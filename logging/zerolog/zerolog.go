@@ -7,7 +7,7 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/caarlos0/env/v11"
+	"github.com/bpurdy1/golang-packages/envparse"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
@@ -37,13 +37,15 @@ type option struct {
 	LogLevel          string `env:"LOG_LEVEL" envDefault:"info"`
 	ConsoleWriter     bool   `env:"LOG_CONSOLE" envDefault:"false"`
 	CallerMarshalFunc func(pc uintptr, file string, line int) string
+	ErrorMarshalFunc  func(err error) interface{}
 	Writer            io.Writer
+	Async             *AsyncWriter
 }
 type Option func(*option)
 
 func NewOption() (*option, error) {
 	var cfg option
-	if err := env.Parse(&cfg); err != nil {
+	if err := envparse.Parse(&cfg); err != nil {
 		return nil, err
 	}
 	return &cfg, nil
@@ -72,6 +74,10 @@ func NewLogger(opts ...Option) zerolog.Logger {
 		zerolog.CallerMarshalFunc = ShortCallerMarshalFunc
 	}
 
+	if cfg.ErrorMarshalFunc != nil {
+		zerolog.ErrorMarshalFunc = cfg.ErrorMarshalFunc
+	}
+
 	level, err := zerolog.ParseLevel(cfg.LogLevel)
 	if err != nil {
 		level = zerolog.InfoLevel
@@ -93,13 +99,19 @@ func NewLogger(opts ...Option) zerolog.Logger {
 		out = zerolog.MultiLevelWriter(w)
 	}
 
+	if cfg.Async != nil {
+		cfg.Async.Start(out)
+		out = cfg.Async
+	}
+
 	newlogger := zerolog.
 		New(out).
 		Level(level).
 		With().
 		Timestamp().
 		Caller(). // Adds file and line number
-		Logger()
+		Logger().
+		Hook(TraceHook{})
 	return newlogger
 }
 
@@ -0,0 +1,193 @@
+package natsclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"github.com/bpurdy1/golang-packages/envparse"
+	"github.com/nats-io/nats.go"
+)
+
+type configWatcherOptions struct {
+	logger  *slog.Logger
+	history uint8
+}
+
+type ConfigWatcherOption func(*configWatcherOptions)
+
+// WithConfigLogger sets the logger used for rejected updates (default:
+// slog.Default()).
+func WithConfigLogger(logger *slog.Logger) ConfigWatcherOption {
+	return func(o *configWatcherOptions) {
+		o.logger = logger
+	}
+}
+
+// WithConfigHistory sets how many revisions the bucket keeps when
+// WatchConfig creates it (default: 5).
+func WithConfigHistory(n uint8) ConfigWatcherOption {
+	return func(o *configWatcherOptions) {
+		o.history = n
+	}
+}
+
+// ConfigWatcher keeps a config struct in sync with a JSON document stored
+// under one key of a JetStream KV bucket.
+type ConfigWatcher[T any] struct {
+	kv     nats.KeyValue
+	key    string
+	logger *slog.Logger
+
+	current  atomic.Pointer[T]
+	revision atomic.Uint64
+
+	mu       sync.Mutex
+	handlers []func(*T)
+
+	done chan struct{}
+}
+
+// WatchConfig loads a T from the environment with envparse, overlays the JSON
+// document stored under key in bucket, and keeps it updated until ctx is
+// cancelled. Fields missing from the document keep their environment values,
+// so a service can move selected settings into the bucket. The bucket is
+// created if it does not exist.
+//
+// Updates that fail to decode or fail envparse validation are logged and
+// ignored. Deleting the key reverts to the environment values.
+//
+//	w, err := natsclient.WatchConfig[FeatureConfig](ctx, js, "config", "billing")
+//	w.OnChange(func(cfg *FeatureConfig) { limiter.SetRate(cfg.RateLimit) })
+//	cfg := w.Current()
+func WatchConfig[T any](ctx context.Context, js nats.JetStreamContext, bucket, key string, opts ...ConfigWatcherOption) (*ConfigWatcher[T], error) {
+	o := configWatcherOptions{
+		logger:  slog.Default(),
+		history: 5,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	kv, err := js.KeyValue(bucket)
+	if errors.Is(err, nats.ErrBucketNotFound) {
+		kv, err = js.CreateKeyValue(&nats.KeyValueConfig{Bucket: bucket, History: o.history})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	w := &ConfigWatcher[T]{
+		kv:     kv,
+		key:    key,
+		logger: o.logger,
+		done:   make(chan struct{}),
+	}
+	base, err := w.fromEnv()
+	if err != nil {
+		return nil, err
+	}
+	w.current.Store(base)
+
+	entry, err := kv.Get(key)
+	switch {
+	case errors.Is(err, nats.ErrKeyNotFound):
+	case err != nil:
+		return nil, err
+	default:
+		cfg, err := w.decode(entry.Value())
+		if err != nil {
+			return nil, err
+		}
+		w.current.Store(cfg)
+		w.revision.Store(entry.Revision())
+	}
+
+	watcher, err := kv.Watch(key, nats.Context(ctx))
+	if err != nil {
+		return nil, err
+	}
+	go w.run(watcher)
+	return w, nil
+}
+
+// Current returns the latest config. It must not be modified.
+func (w *ConfigWatcher[T]) Current() *T {
+	return w.current.Load()
+}
+
+// OnChange registers fn to be called with the new config after each applied
+// update.
+func (w *ConfigWatcher[T]) OnChange(fn func(cfg *T)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handlers = append(w.handlers, fn)
+}
+
+// Done is closed once the watcher has stopped.
+func (w *ConfigWatcher[T]) Done() <-chan struct{} {
+	return w.done
+}
+
+func (w *ConfigWatcher[T]) run(watcher nats.KeyWatcher) {
+	defer close(w.done)
+	defer watcher.Stop()
+
+	for entry := range watcher.Updates() {
+		// A nil entry marks the end of the initial values.
+		if entry == nil || entry.Revision() <= w.revision.Load() {
+			continue
+		}
+
+		var cfg *T
+		var err error
+		if entry.Operation() == nats.KeyValuePut {
+			cfg, err = w.decode(entry.Value())
+		} else {
+			cfg, err = w.fromEnv()
+		}
+		if err != nil {
+			w.logger.Error("natsclient: rejected config update",
+				"key", w.key, "revision", entry.Revision(), "error", err)
+			continue
+		}
+		w.current.Store(cfg)
+		w.revision.Store(entry.Revision())
+
+		w.mu.Lock()
+		handlers := w.handlers
+		w.mu.Unlock()
+		for _, fn := range handlers {
+			fn(cfg)
+		}
+	}
+}
+
+// fromEnv returns a new T parsed from the environment. Every config is
+// built from scratch, so configs never share slices or maps and decoding an
+// update cannot modify one already returned by Current.
+func (w *ConfigWatcher[T]) fromEnv() (*T, error) {
+	cfg := new(T)
+	if err := envparse.Parse(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// decode overlays data on the environment values.
+func (w *ConfigWatcher[T]) decode(data []byte) (*T, error) {
+	cfg, err := w.fromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, err
+	}
+	if err := envparse.Validate(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,115 @@
+package natsclient
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+type fakeEntry struct {
+	value    []byte
+	revision uint64
+	op       nats.KeyValueOp
+}
+
+func (e fakeEntry) Bucket() string             { return "config" }
+func (e fakeEntry) Key() string                { return "billing" }
+func (e fakeEntry) Value() []byte              { return e.value }
+func (e fakeEntry) Revision() uint64           { return e.revision }
+func (e fakeEntry) Created() time.Time         { return time.Time{} }
+func (e fakeEntry) Delta() uint64              { return 0 }
+func (e fakeEntry) Operation() nats.KeyValueOp { return e.op }
+
+type fakeKeyWatcher struct {
+	updates chan nats.KeyValueEntry
+}
+
+func (w *fakeKeyWatcher) Context() context.Context           { return context.Background() }
+func (w *fakeKeyWatcher) Updates() <-chan nats.KeyValueEntry { return w.updates }
+func (w *fakeKeyWatcher) Stop() error                        { return nil }
+func (w *fakeKeyWatcher) Error() <-chan error                { return nil }
+
+type billingConfig struct {
+	Regions []string          `env:"TEST_CONFIG_REGIONS" envDefault:"us,eu"`
+	Limits  map[string]string `env:"TEST_CONFIG_LIMITS" envDefault:"free:10"`
+}
+
+// watchFake runs a ConfigWatcher over a fake KV watcher and returns every
+// config it applies, in order.
+func watchFake(t *testing.T, entries ...nats.KeyValueEntry) (*ConfigWatcher[billingConfig], []*billingConfig) {
+	t.Helper()
+	w := &ConfigWatcher[billingConfig]{
+		key:    "billing",
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		done:   make(chan struct{}),
+	}
+	base, err := w.fromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.current.Store(base)
+
+	applied := []*billingConfig{base}
+	w.OnChange(func(cfg *billingConfig) { applied = append(applied, cfg) })
+
+	watcher := &fakeKeyWatcher{updates: make(chan nats.KeyValueEntry, len(entries))}
+	for _, e := range entries {
+		watcher.updates <- e
+	}
+	close(watcher.updates)
+	w.run(watcher)
+	return w, applied
+}
+
+func TestConfigWatcher_UpdatesDoNotShareState(t *testing.T) {
+	w, applied := watchFake(t,
+		fakeEntry{value: []byte(`{"Regions":["ap"],"Limits":{"pro":"100"}}`), revision: 1, op: nats.KeyValuePut},
+		fakeEntry{value: []byte(`{"Regions":["sa","af"],"Limits":{"free":"5"}}`), revision: 2, op: nats.KeyValuePut},
+		fakeEntry{revision: 3, op: nats.KeyValueDelete},
+	)
+	if len(applied) != 4 {
+		t.Fatalf("applied %d configs, want 4", len(applied))
+	}
+
+	tests := []billingConfig{
+		{Regions: []string{"us", "eu"}, Limits: map[string]string{"free": "10"}},
+		{Regions: []string{"ap"}, Limits: map[string]string{"free": "10", "pro": "100"}},
+		{Regions: []string{"sa", "af"}, Limits: map[string]string{"free": "5"}},
+		{Regions: []string{"us", "eu"}, Limits: map[string]string{"free": "10"}},
+	}
+	for i, want := range tests {
+		got := applied[i]
+		if !slices.Equal(got.Regions, want.Regions) || len(got.Limits) != len(want.Limits) {
+			t.Errorf("config %d = %+v, want %+v", i, *got, want)
+			continue
+		}
+		for k, v := range want.Limits {
+			if got.Limits[k] != v {
+				t.Errorf("config %d = %+v, want %+v", i, *got, want)
+				break
+			}
+		}
+	}
+	if w.Current() != applied[3] || w.revision.Load() != 3 {
+		t.Errorf("Current is not the config from revision 3")
+	}
+}
+
+func TestConfigWatcher_RejectsInvalidUpdate(t *testing.T) {
+	w, applied := watchFake(t,
+		fakeEntry{value: []byte(`{"Regions":["ap"]}`), revision: 1, op: nats.KeyValuePut},
+		fakeEntry{value: []byte(`{"Regions":`), revision: 2, op: nats.KeyValuePut},
+		fakeEntry{value: []byte(`{"Regions":["stale"]}`), revision: 1, op: nats.KeyValuePut},
+	)
+	if len(applied) != 2 {
+		t.Fatalf("applied %d configs, want 2", len(applied))
+	}
+	if got := w.Current().Regions; !slices.Equal(got, []string{"ap"}) {
+		t.Errorf("Regions = %v, want [ap]", got)
+	}
+}
@@ -5,22 +5,22 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/caarlos0/env/v11"
+	"github.com/bpurdy1/golang-packages/envparse"
 	"github.com/nats-io/nats.go"
 )
 
 // Config holds the connection parameters for NATS
 type Config struct {
 	URL      string `env:"NATS_URL" envDefault:"nats://localhost:4222"`
-	Token    string `env:"NATS_TOKEN"`
+	Token    string `env:"NATS_TOKEN" sensitive:"true"`
 	User     string `env:"NATS_USER"`
-	Password string `env:"NATS_PASS"`
+	Password string `env:"NATS_PASS" sensitive:"true"`
 }
 
 // NewConfig parses environment variables into the Config struct
 func NewConfig() (*Config, error) {
 	cfg := &Config{}
-	if err := env.Parse(cfg); err != nil {
+	if err := envparse.Parse(cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse nats config: %w", err)
 	}
 	return cfg, nil
@@ -0,0 +1,154 @@
+package natsclient
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// RequesterStats is a snapshot of request counters.
+type RequesterStats struct {
+	Requests uint64
+	Timeouts uint64
+	InFlight int64
+}
+
+// publisherSubscriber is the part of a connection a Requester uses. *NatsClient
+// and *nats.Conn implement it.
+type publisherSubscriber interface {
+	PublishRequest(subj, reply string, data []byte) error
+	Subscribe(subj string, cb nats.MsgHandler) (*nats.Subscription, error)
+}
+
+// Requester sends requests over a single wildcard inbox subscription shared
+// by all calls.
+//
+// nats.Conn.RequestWithContext multiplexes replies the same way. Requester
+// adds a default timeout for contexts without a deadline, counters for
+// requests, timeouts and in-flight calls, and its own inbox, which Close
+// removes without affecting other users of the connection.
+type Requester struct {
+	client  publisherSubscriber
+	prefix  string
+	sub     *nats.Subscription
+	timeout time.Duration
+
+	mu      sync.Mutex
+	pending map[string]chan *nats.Msg
+	next    atomic.Uint64
+
+	requests atomic.Uint64
+	timeouts atomic.Uint64
+	inFlight atomic.Int64
+}
+
+type RequesterOption func(*Requester)
+
+// WithRequestTimeout sets the timeout for requests whose context has no
+// deadline (default: 5s).
+func WithRequestTimeout(d time.Duration) RequesterOption {
+	return func(r *Requester) {
+		r.timeout = d
+	}
+}
+
+// NewRequester subscribes to a wildcard inbox used for all replies.
+func NewRequester(client publisherSubscriber, opts ...RequesterOption) (*Requester, error) {
+	r := &Requester{
+		client:  client,
+		prefix:  nats.NewInbox() + ".",
+		timeout: 5 * time.Second,
+		pending: make(map[string]chan *nats.Msg),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	sub, err := client.Subscribe(r.prefix+"*", r.dispatch)
+	if err != nil {
+		return nil, err
+	}
+	r.sub = sub
+	return r, nil
+}
+
+// Request publishes data on subj and waits for the reply until ctx is done.
+// It returns nats.ErrTimeout if the deadline passes and nats.ErrNoResponders
+// if nobody is subscribed to subj.
+func (r *Requester) Request(ctx context.Context, subj string, data []byte) (*nats.Msg, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	token := strconv.FormatUint(r.next.Add(1), 36)
+	ch := make(chan *nats.Msg, 1)
+
+	r.mu.Lock()
+	r.pending[token] = ch
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		delete(r.pending, token)
+		r.mu.Unlock()
+	}()
+
+	r.requests.Add(1)
+	r.inFlight.Add(1)
+	defer r.inFlight.Add(-1)
+
+	if err := r.client.PublishRequest(subj, r.prefix+token, data); err != nil {
+		return nil, err
+	}
+
+	select {
+	case msg := <-ch:
+		if len(msg.Data) == 0 && msg.Header.Get("Status") == "503" {
+			return nil, nats.ErrNoResponders
+		}
+		return msg, nil
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			r.timeouts.Add(1)
+			return nil, nats.ErrTimeout
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// Stats returns the current request counters.
+func (r *Requester) Stats() RequesterStats {
+	return RequesterStats{
+		Requests: r.requests.Load(),
+		Timeouts: r.timeouts.Load(),
+		InFlight: r.inFlight.Load(),
+	}
+}
+
+// Close unsubscribes from the reply inbox. Pending requests time out.
+func (r *Requester) Close() error {
+	return r.sub.Unsubscribe()
+}
+
+func (r *Requester) dispatch(msg *nats.Msg) {
+	token := msg.Subject[len(r.prefix):]
+
+	r.mu.Lock()
+	ch, ok := r.pending[token]
+	r.mu.Unlock()
+	if !ok {
+		// Late reply to a request that already timed out.
+		return
+	}
+
+	select {
+	case ch <- msg:
+	default:
+	}
+}
@@ -0,0 +1,165 @@
+package natsclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+)
+
+// newTestClient starts an in-process server with JetStream enabled and
+// connects to it.
+func newTestClient(t *testing.T) *NatsClient {
+	t.Helper()
+	opts := natsserver.DefaultTestOptions
+	opts.Port = server.RANDOM_PORT
+	opts.JetStream = true
+	opts.StoreDir = t.TempDir()
+	srv := natsserver.RunServer(&opts)
+	t.Cleanup(srv.Shutdown)
+
+	client, err := NewClientOptions(func(o *nats.Options) { o.Url = srv.ClientURL() })
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+	return client.(*NatsClient)
+}
+
+func pendingCount(r *Requester) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.pending)
+}
+
+func TestRequester_CorrelatesReplies(t *testing.T) {
+	client := newTestClient(t)
+	// Replies are sent in reverse order of arrival, so each must be matched
+	// to its request by the reply subject.
+	var mu sync.Mutex
+	var held []*nats.Msg
+	_, err := client.Subscribe("echo", func(msg *nats.Msg) {
+		mu.Lock()
+		defer mu.Unlock()
+		held = append(held, msg)
+		if len(held) == 5 {
+			for i := len(held) - 1; i >= 0; i-- {
+				_ = held[i].Respond(append([]byte("re:"), held[i].Data...))
+			}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRequester(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	var wg sync.WaitGroup
+	for i := range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data := fmt.Sprint(i)
+			msg, err := r.Request(context.Background(), "echo", []byte(data))
+			if err != nil {
+				t.Errorf("request %d: %v", i, err)
+				return
+			}
+			if string(msg.Data) != "re:"+data {
+				t.Errorf("request %d got reply %q", i, msg.Data)
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := r.Stats()
+	if stats.Requests != 5 || stats.Timeouts != 0 || stats.InFlight != 0 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+	if n := pendingCount(r); n != 0 {
+		t.Errorf("%d pending entries left after replies", n)
+	}
+}
+
+func TestRequester_Timeout(t *testing.T) {
+	client := newTestClient(t)
+	late := make(chan *nats.Msg, 1)
+	if _, err := client.Subscribe("slow", func(msg *nats.Msg) { late <- msg }); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRequester(client, WithRequestTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	if _, err := r.Request(context.Background(), "slow", nil); !errors.Is(err, nats.ErrTimeout) {
+		t.Fatalf("Request = %v, want nats.ErrTimeout", err)
+	}
+	if n := pendingCount(r); n != 0 {
+		t.Errorf("%d pending entries left after timeout", n)
+	}
+	// A reply arriving after the timeout is dropped.
+	if err := (<-late).Respond([]byte("too late")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := r.Request(ctx, "slow", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Request with cancelled context = %v, want context.Canceled", err)
+	}
+
+	stats := r.Stats()
+	if stats.Requests != 2 || stats.Timeouts != 1 || stats.InFlight != 0 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
+
+func TestRequester_NoResponders(t *testing.T) {
+	client := newTestClient(t)
+	r, err := NewRequester(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	if _, err := r.Request(context.Background(), "nobody", nil); !errors.Is(err, nats.ErrNoResponders) {
+		t.Errorf("Request = %v, want nats.ErrNoResponders", err)
+	}
+}
+
+func TestRequester_CloseRemovesInbox(t *testing.T) {
+	client := newTestClient(t)
+	if _, err := client.Subscribe("echo", func(msg *nats.Msg) { _ = msg.Respond(msg.Data) }); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRequester(client, WithRequestTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if r.sub.IsValid() {
+		t.Error("inbox subscription still valid after Close")
+	}
+	if _, err := r.Request(context.Background(), "echo", nil); !errors.Is(err, nats.ErrTimeout) {
+		t.Errorf("Request after Close = %v, want nats.ErrTimeout", err)
+	}
+}
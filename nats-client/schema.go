@@ -0,0 +1,218 @@
+package natsclient
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// ErrSchemaViolation is matched by every *SchemaError.
+var ErrSchemaViolation = errors.New("natsclient: schema violation")
+
+var errNoPublishRequest = errors.New("natsclient: wrapped client does not support PublishRequest")
+
+// SchemaError reports a payload that does not match the schema registered for
+// its subject.
+type SchemaError struct {
+	Subject string
+	Err     error
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("natsclient: payload on %q does not match schema: %v", e.Subject, e.Err)
+}
+
+func (e *SchemaError) Unwrap() []error {
+	return []error{ErrSchemaViolation, e.Err}
+}
+
+type subjectSchema struct {
+	pattern string
+	schema  *jsonschema.Schema
+}
+
+// SchemaRegistry maps subjects to JSON Schemas. Subjects may use the NATS
+// wildcards "*" and ">"; when several patterns match, the first registered
+// wins. Subjects without a schema are not validated.
+type SchemaRegistry struct {
+	mu         sync.RWMutex
+	schemas    []subjectSchema
+	violations map[string]uint64
+}
+
+// NewSchemaRegistry creates an empty registry.
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{violations: make(map[string]uint64)}
+}
+
+// Register compiles schema and associates it with subject.
+//
+//	registry.Register("orders.*.created", []byte(`{
+//		"type": "object",
+//		"required": ["order_id", "amount"]
+//	}`))
+func (r *SchemaRegistry) Register(subject string, schema []byte) error {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schema))
+	if err != nil {
+		return fmt.Errorf("schema for %q: %w", subject, err)
+	}
+
+	url := "mem://" + subject + ".json"
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource(url, doc); err != nil {
+		return fmt.Errorf("schema for %q: %w", subject, err)
+	}
+	compiled, err := c.Compile(url)
+	if err != nil {
+		return fmt.Errorf("schema for %q: %w", subject, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schemas = append(r.schemas, subjectSchema{pattern: subject, schema: compiled})
+	return nil
+}
+
+// Validate checks data against the schema registered for subject. It returns
+// a *SchemaError if the payload is not valid JSON or does not match.
+func (r *SchemaRegistry) Validate(subject string, data []byte) error {
+	r.mu.RLock()
+	var match *subjectSchema
+	for i := range r.schemas {
+		if subjectMatches(r.schemas[i].pattern, subject) {
+			match = &r.schemas[i]
+			break
+		}
+	}
+	r.mu.RUnlock()
+	if match == nil {
+		return nil
+	}
+
+	v, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err == nil {
+		err = match.schema.Validate(v)
+	}
+	if err == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	r.violations[match.pattern]++
+	r.mu.Unlock()
+	return &SchemaError{Subject: subject, Err: err}
+}
+
+// Violations returns the number of schema violations seen per registered
+// subject pattern.
+func (r *SchemaRegistry) Violations() map[string]uint64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make(map[string]uint64, len(r.violations))
+	for k, v := range r.violations {
+		out[k] = v
+	}
+	return out
+}
+
+// subjectMatches reports whether subject matches a pattern that may contain
+// the NATS wildcards "*" (one token) and ">" (one or more trailing tokens).
+func subjectMatches(pattern, subject string) bool {
+	pt := strings.Split(pattern, ".")
+	st := strings.Split(subject, ".")
+	for i, p := range pt {
+		if p == ">" {
+			return len(st) > i
+		}
+		if i >= len(st) || (p != "*" && p != st[i]) {
+			return false
+		}
+	}
+	return len(pt) == len(st)
+}
+
+type validatingClient struct {
+	Client
+	registry  *SchemaRegistry
+	onInvalid func(msg *nats.Msg, err error)
+}
+
+type SchemaOption func(*validatingClient)
+
+// WithInvalidMessageHandler sets the function called for received messages
+// that fail validation. Such messages are never passed to the subscriber's
+// handler. By default they are logged with slog.
+func WithInvalidMessageHandler(fn func(msg *nats.Msg, err error)) SchemaOption {
+	return func(c *validatingClient) {
+		c.onInvalid = fn
+	}
+}
+
+// WithSchemas wraps client so Publish, PublishRequest and Request reject
+// payloads that do not match registry, returning a *SchemaError, and Subscribe
+// and QueueSubscribe drop invalid messages before they reach the handler.
+func WithSchemas(client Client, registry *SchemaRegistry, opts ...SchemaOption) Client {
+	c := &validatingClient{
+		Client:   client,
+		registry: registry,
+		onInvalid: func(msg *nats.Msg, err error) {
+			slog.Warn("dropping message that does not match schema", "subject", msg.Subject, "error", err)
+		},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *validatingClient) Publish(subj string, data []byte) error {
+	if err := c.registry.Validate(subj, data); err != nil {
+		return err
+	}
+	return c.Client.Publish(subj, data)
+}
+
+// PublishRequest lets a Requester use the wrapped client. It fails if the
+// wrapped client has no PublishRequest method.
+func (c *validatingClient) PublishRequest(subj, reply string, data []byte) error {
+	if err := c.registry.Validate(subj, data); err != nil {
+		return err
+	}
+	p, ok := c.Client.(publisherSubscriber)
+	if !ok {
+		return errNoPublishRequest
+	}
+	return p.PublishRequest(subj, reply, data)
+}
+
+func (c *validatingClient) Request(subj string, data []byte, timeout time.Duration) (*nats.Msg, error) {
+	if err := c.registry.Validate(subj, data); err != nil {
+		return nil, err
+	}
+	return c.Client.Request(subj, data, timeout)
+}
+
+func (c *validatingClient) Subscribe(subj string, cb nats.MsgHandler) (*nats.Subscription, error) {
+	return c.Client.Subscribe(subj, c.validate(cb))
+}
+
+func (c *validatingClient) QueueSubscribe(subj, queue string, cb nats.MsgHandler) (*nats.Subscription, error) {
+	return c.Client.QueueSubscribe(subj, queue, c.validate(cb))
+}
+
+func (c *validatingClient) validate(cb nats.MsgHandler) nats.MsgHandler {
+	return func(msg *nats.Msg) {
+		if err := c.registry.Validate(msg.Subject, msg.Data); err != nil {
+			c.onInvalid(msg, err)
+			return
+		}
+		cb(msg)
+	}
+}
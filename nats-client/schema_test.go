@@ -0,0 +1,170 @@
+package natsclient
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+const orderSchema = `{
+	"type": "object",
+	"required": ["order_id", "amount"],
+	"properties": {
+		"order_id": {"type": "string"},
+		"amount": {"type": "number"}
+	}
+}`
+
+func TestSubjectMatches(t *testing.T) {
+	tests := []struct {
+		pattern, subject string
+		want             bool
+	}{
+		{"orders.created", "orders.created", true},
+		{"orders.created", "orders.deleted", false},
+		{"orders.created", "orders.created.eu", false},
+		{"orders.created.eu", "orders.created", false},
+		{"orders.*", "orders.created", true},
+		{"orders.*", "orders", false},
+		{"orders.*", "orders.created.eu", false},
+		{"orders.*.created", "orders.eu.created", true},
+		{"orders.*.created", "orders.eu.deleted", false},
+		{"*.created", "orders.created", true},
+		{"orders.>", "orders.created", true},
+		{"orders.>", "orders.created.eu", true},
+		{"orders.>", "orders", false},
+		{">", "orders", true},
+		{"orders.*.>", "orders.eu.created.v1", true},
+		{"orders.*.>", "orders.eu", false},
+	}
+	for _, tt := range tests {
+		if got := subjectMatches(tt.pattern, tt.subject); got != tt.want {
+			t.Errorf("subjectMatches(%q, %q) = %v, want %v", tt.pattern, tt.subject, got, tt.want)
+		}
+	}
+}
+
+func TestSchemaRegistry_Validate(t *testing.T) {
+	r := NewSchemaRegistry()
+	if err := r.Register("orders.*.created", []byte(orderSchema)); err != nil {
+		t.Fatal(err)
+	}
+	// Registered later, so orders.*.created takes precedence where both match.
+	if err := r.Register("orders.>", []byte(`{"type": "object"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		subject string
+		data    string
+		valid   bool
+	}{
+		{"valid", "orders.eu.created", `{"order_id": "o-1", "amount": 10}`, true},
+		{"missing field", "orders.eu.created", `{"order_id": "o-1"}`, false},
+		{"wrong type", "orders.eu.created", `{"order_id": 1, "amount": 10}`, false},
+		{"not json", "orders.eu.created", `{"order_id":`, false},
+		{"first pattern wins", "orders.us.created", `{}`, false},
+		{"fallback pattern", "orders.eu.deleted", `{}`, true},
+		{"fallback pattern invalid", "orders.eu.deleted", `[]`, false},
+		{"no schema", "payments.eu.created", `not json`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.Validate(tt.subject, []byte(tt.data))
+			if tt.valid {
+				if err != nil {
+					t.Errorf("Validate = %v, want nil", err)
+				}
+				return
+			}
+			var schemaErr *SchemaError
+			if !errors.As(err, &schemaErr) || schemaErr.Subject != tt.subject {
+				t.Fatalf("Validate = %v, want *SchemaError for %q", err, tt.subject)
+			}
+			if !errors.Is(err, ErrSchemaViolation) {
+				t.Errorf("Validate error does not match ErrSchemaViolation")
+			}
+		})
+	}
+
+	want := map[string]uint64{"orders.*.created": 4, "orders.>": 1}
+	got := r.Violations()
+	if len(got) != len(want) {
+		t.Errorf("Violations() = %v, want %v", got, want)
+	}
+	for pattern, n := range want {
+		if got[pattern] != n {
+			t.Errorf("Violations()[%q] = %d, want %d", pattern, got[pattern], n)
+		}
+	}
+}
+
+func TestSchemaRegistry_RegisterInvalid(t *testing.T) {
+	r := NewSchemaRegistry()
+	if err := r.Register("orders.created", []byte(`{"type":`)); err == nil {
+		t.Error("Register accepted malformed JSON")
+	}
+	if err := r.Register("orders.created", []byte(`{"type": "nope"}`)); err == nil {
+		t.Error("Register accepted an invalid schema")
+	}
+}
+
+func TestWithSchemas(t *testing.T) {
+	registry := NewSchemaRegistry()
+	if err := registry.Register("orders.created", []byte(orderSchema)); err != nil {
+		t.Fatal(err)
+	}
+	conn := newTestClient(t)
+
+	invalid := make(chan *nats.Msg, 1)
+	client := WithSchemas(conn, registry, WithInvalidMessageHandler(func(msg *nats.Msg, err error) {
+		invalid <- msg
+	}))
+
+	received := make(chan *nats.Msg, 1)
+	if _, err := client.Subscribe("orders.created", func(msg *nats.Msg) { received <- msg }); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Publish("orders.created", []byte(`{}`)); !errors.Is(err, ErrSchemaViolation) {
+		t.Errorf("Publish of invalid payload = %v, want ErrSchemaViolation", err)
+	}
+
+	// Invalid messages published around the wrapper are dropped on receipt.
+	if err := conn.Publish("orders.created", []byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case msg := <-invalid:
+		if string(msg.Data) != `{}` {
+			t.Errorf("invalid handler got %q", msg.Data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("invalid message handler not called")
+	}
+
+	valid := `{"order_id": "o-1", "amount": 10}`
+	if err := client.Publish("orders.created", []byte(valid)); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case msg := <-received:
+		if string(msg.Data) != valid {
+			t.Errorf("subscriber got %q, want %q", msg.Data, valid)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("valid message not delivered")
+	}
+	select {
+	case msg := <-received:
+		t.Errorf("subscriber got unexpected message %q", msg.Data)
+	default:
+	}
+
+	if n := registry.Violations()["orders.created"]; n != 2 {
+		t.Errorf("violations = %d, want 2", n)
+	}
+}
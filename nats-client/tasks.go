@@ -0,0 +1,301 @@
+package natsclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// deliverAtHeader carries the earliest delivery time of a delayed task.
+const deliverAtHeader = "Tasks-Deliver-At"
+
+// Task is a unit of work received by a task handler.
+type Task struct {
+	Subject string
+	Payload []byte
+	// Attempt is the 1-based delivery attempt of this task.
+	Attempt int
+}
+
+// TaskHandler processes a task. Returning an error schedules a retry with
+// backoff until the worker's maximum number of attempts is reached.
+type TaskHandler func(ctx context.Context, task *Task) error
+
+// TaskStats is a snapshot of task counters.
+type TaskStats struct {
+	Enqueued   uint64
+	Succeeded  uint64
+	Failed     uint64
+	Terminated uint64
+	InFlight   int64
+}
+
+// Tasks is a durable task queue on a JetStream work-queue stream. Each task
+// is delivered to exactly one worker and retried on failure.
+type Tasks struct {
+	js     nats.JetStreamContext
+	stream string
+
+	enqueued   atomic.Uint64
+	succeeded  atomic.Uint64
+	failed     atomic.Uint64
+	terminated atomic.Uint64
+	inFlight   atomic.Int64
+}
+
+// NewTasks creates a task queue on stream, creating the stream with
+// work-queue retention if it does not exist. Tasks are published on subjects
+// under "<stream>.".
+//
+//	js, err := nc.JetStream()
+//	tasks, err := natsclient.NewTasks(js, "TASKS")
+func NewTasks(js nats.JetStreamContext, stream string) (*Tasks, error) {
+	if _, err := js.StreamInfo(stream); errors.Is(err, nats.ErrStreamNotFound) {
+		_, err = js.AddStream(&nats.StreamConfig{
+			Name:      stream,
+			Subjects:  []string{stream + ".>"},
+			Retention: nats.WorkQueuePolicy,
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &Tasks{js: js, stream: stream}, nil
+}
+
+// Enqueue publishes a task on subject. A positive delay holds the task back
+// until the delay has elapsed.
+func (t *Tasks) Enqueue(ctx context.Context, subject string, payload []byte, delay time.Duration) error {
+	msg := nats.NewMsg(t.stream + "." + subject)
+	msg.Data = payload
+	if delay > 0 {
+		msg.Header.Set(deliverAtHeader, time.Now().Add(delay).UTC().Format(time.RFC3339Nano))
+	}
+
+	if _, err := t.js.PublishMsg(msg, nats.Context(ctx)); err != nil {
+		return err
+	}
+	t.enqueued.Add(1)
+	return nil
+}
+
+// Stats returns the current task counters.
+func (t *Tasks) Stats() TaskStats {
+	return TaskStats{
+		Enqueued:   t.enqueued.Load(),
+		Succeeded:  t.succeeded.Load(),
+		Failed:     t.failed.Load(),
+		Terminated: t.terminated.Load(),
+		InFlight:   t.inFlight.Load(),
+	}
+}
+
+type worker struct {
+	maxInFlight int
+	maxAttempts int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+	ackWait     time.Duration
+}
+
+type WorkerOption func(*worker)
+
+// WithMaxInFlight sets how many tasks a worker processes concurrently (default: 1).
+func WithMaxInFlight(n int) WorkerOption {
+	return func(w *worker) {
+		w.maxInFlight = n
+	}
+}
+
+// WithMaxAttempts sets how many times a task is attempted before it is
+// terminated (default: 5).
+func WithMaxAttempts(n int) WorkerOption {
+	return func(w *worker) {
+		w.maxAttempts = n
+	}
+}
+
+// WithBackoff sets the base and maximum retry delay. The delay doubles with
+// each attempt (default: 1s, 1m).
+func WithBackoff(base, maxDelay time.Duration) WorkerOption {
+	return func(w *worker) {
+		w.baseBackoff = base
+		w.maxBackoff = maxDelay
+	}
+}
+
+// WithAckWait sets how long JetStream waits for a worker that has stopped
+// responding before redelivering its task (default: 30s). Tasks running
+// longer than this are kept alive with progress acks.
+func WithAckWait(d time.Duration) WorkerOption {
+	return func(w *worker) {
+		w.ackWait = d
+	}
+}
+
+// Handle runs handler for tasks on subject until ctx is cancelled. Workers
+// handling the same subject share a durable consumer, so each task is
+// processed once across all of them.
+func (t *Tasks) Handle(ctx context.Context, subject string, handler TaskHandler, opts ...WorkerOption) error {
+	w := &worker{
+		maxInFlight: 1,
+		maxAttempts: 5,
+		baseBackoff: time.Second,
+		maxBackoff:  time.Minute,
+		ackWait:     30 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	sub, err := t.js.PullSubscribe(t.stream+"."+subject, DurableName("tasks", subject),
+		nats.BindStream(t.stream),
+		nats.ManualAck(),
+		nats.AckWait(w.ackWait),
+	)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe() //nolint:errcheck // best effort on shutdown
+
+	sem := make(chan struct{}, w.maxInFlight)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for ctx.Err() == nil {
+		free := w.maxInFlight - len(sem)
+		if free == 0 {
+			select {
+			case sem <- struct{}{}:
+				<-sem
+			case <-ctx.Done():
+			}
+			continue
+		}
+
+		msgs, err := sub.Fetch(free, nats.MaxWait(time.Second))
+		if err != nil {
+			if errors.Is(err, nats.ErrTimeout) || ctx.Err() != nil {
+				continue
+			}
+			return err
+		}
+
+		for _, msg := range msgs {
+			sem <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				t.process(ctx, w, msg, handler)
+			}()
+		}
+	}
+	return nil
+}
+
+func (t *Tasks) process(ctx context.Context, w *worker, msg *nats.Msg, handler TaskHandler) {
+	meta, err := msg.Metadata()
+	if err != nil {
+		_ = msg.Term()
+		return
+	}
+	attempt := int(meta.NumDelivered)
+
+	if at := msg.Header.Get(deliverAtHeader); at != "" {
+		if deliverAt, err := time.Parse(time.RFC3339Nano, at); err == nil {
+			if wait := time.Until(deliverAt); wait > 0 {
+				_ = msg.NakWithDelay(wait)
+				return
+			}
+			// The delay consumed one delivery; don't count it as an attempt.
+			if attempt > 1 {
+				attempt--
+			}
+		}
+	}
+
+	t.inFlight.Add(1)
+	defer t.inFlight.Add(-1)
+
+	done := make(chan struct{})
+	defer close(done)
+	go keepAlive(done, w.ackWait/2, msg.InProgress)
+
+	task := &Task{
+		Subject: strings.TrimPrefix(msg.Subject, t.stream+"."),
+		Payload: msg.Data,
+		Attempt: attempt,
+	}
+	if err := handler(ctx, task); err != nil {
+		t.failed.Add(1)
+		if attempt >= w.maxAttempts {
+			t.terminated.Add(1)
+			_ = msg.Term()
+			return
+		}
+		_ = msg.NakWithDelay(w.backoff(attempt))
+		return
+	}
+
+	t.succeeded.Add(1)
+	_ = msg.Ack()
+}
+
+func (w *worker) backoff(attempt int) time.Duration {
+	d := w.baseBackoff
+	for i := 1; i < attempt && d < w.maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, w.maxBackoff)
+}
+
+// keepAlive calls inProgress every interval until done is closed, so
+// JetStream does not redeliver a task that is still running.
+func keepAlive(done <-chan struct{}, interval time.Duration, inProgress func(...nats.AckOpt) error) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_ = inProgress()
+		case <-done:
+			return
+		}
+	}
+}
+
+// DurableName derives a JetStream consumer name for subject, which may
+// contain characters that are not allowed in consumer names. Bytes of subject
+// other than ASCII letters, digits and '-' are written as '_' followed by
+// their hex value, so distinct subjects always get distinct names. prefix is
+// used as is and joined with '_'.
+//
+//	DurableName("billing", "orders.*") // "billing_orders_2e_2a"
+func DurableName(prefix, subject string) string {
+	var b strings.Builder
+	b.WriteString(prefix)
+	b.WriteByte('_')
+	for i := 0; i < len(subject); i++ {
+		c := subject[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', c == '-':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "_%02x", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,81 @@
+package natsclient
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+func TestDurableName(t *testing.T) {
+	tests := map[string]string{
+		"email":        "tasks_email",
+		"email.send":   "tasks_email_2esend",
+		"email_send":   "tasks_email_5fsend",
+		"email.*":      "tasks_email_2e_2a",
+		"email.any":    "tasks_email_2eany",
+		"reports.>":    "tasks_reports_2e_3e",
+		"reports.all":  "tasks_reports_2eall",
+		"Billing-2024": "tasks_Billing-2024",
+	}
+	seen := map[string]string{}
+	for subject, want := range tests {
+		got := DurableName("tasks", subject)
+		if got != want {
+			t.Errorf("DurableName(%q) = %q, want %q", subject, got, want)
+		}
+		if other, ok := seen[got]; ok {
+			t.Errorf("%q and %q share durable name %q", subject, other, got)
+		}
+		seen[got] = subject
+	}
+}
+
+func TestWorkerBackoff(t *testing.T) {
+	w := &worker{baseBackoff: time.Second, maxBackoff: 5 * time.Second}
+
+	tests := map[int]time.Duration{
+		1:  time.Second,
+		2:  2 * time.Second,
+		3:  4 * time.Second,
+		4:  5 * time.Second,
+		50: 5 * time.Second,
+	}
+	for attempt, want := range tests {
+		if got := w.backoff(attempt); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
+
+func TestKeepAlive(t *testing.T) {
+	var calls atomic.Int32
+	inProgress := func(...nats.AckOpt) error {
+		calls.Add(1)
+		return nil
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		keepAlive(done, 10*time.Millisecond, inProgress)
+		close(stopped)
+	}()
+
+	for calls.Load() < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	close(done)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("keepAlive did not stop")
+	}
+
+	n := calls.Load()
+	time.Sleep(30 * time.Millisecond)
+	if calls.Load() != n {
+		t.Error("keepAlive sent progress acks after done was closed")
+	}
+}
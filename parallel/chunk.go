@@ -0,0 +1,56 @@
+package parallel
+
+import (
+	"context"
+	"runtime"
+)
+
+// ChunkSlice splits data into consecutive chunks of at most size elements. The
+// chunks share data's backing array. A size below 1 yields a single chunk.
+func ChunkSlice[T any](data []T, size int) [][]T {
+	if len(data) == 0 {
+		return nil
+	}
+	if size < 1 {
+		size = len(data)
+	}
+	chunks := make([][]T, 0, (len(data)+size-1)/size)
+	for size < len(data) {
+		data, chunks = data[size:], append(chunks, data[:size:size])
+	}
+	return append(chunks, data)
+}
+
+// ProcessChunks splits data into chunks of chunkSize and runs fn over them on
+// workers goroutines, returning one result per chunk in order. It is meant for
+// CPU-bound work; workers below 1 defaults to GOMAXPROCS, and chunkSize below
+// 1 spreads data over four chunks per worker to even out uneven chunks.
+//
+// Results are checked in chunk order, and the first error found is returned.
+// It cancels the context passed to fn and stops new chunks from starting, but
+// chunks that have already started run to completion unless fn returns early
+// on ctx.Done(). ProcessChunks does not wait for them before returning.
+func ProcessChunks[T, R any](ctx context.Context, data []T, chunkSize, workers int, fn func(context.Context, []T) (R, error)) ([]R, error) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if chunkSize < 1 {
+		chunkSize = max(1, (len(data)+4*workers-1)/(4*workers))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	chunks := ChunkSlice(data, chunkSize)
+	results := make([]R, 0, len(chunks))
+	for r := range Stream(ctx, chunks, workers, fn) {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		results = append(results, r.Value)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,62 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestChunkSlice(t *testing.T) {
+	chunks := ChunkSlice([]int{1, 2, 3, 4, 5}, 2)
+	if len(chunks) != 3 || len(chunks[0]) != 2 || len(chunks[2]) != 1 || chunks[2][0] != 5 {
+		t.Fatalf("unexpected chunks: %v", chunks)
+	}
+	// Appending to a chunk must not overwrite the next one.
+	_ = append(chunks[0], 99)
+	if chunks[1][0] != 3 {
+		t.Errorf("append to chunk overwrote neighbour: %v", chunks)
+	}
+
+	if got := ChunkSlice([]int{}, 2); got != nil {
+		t.Errorf("expected nil for empty input, got %v", got)
+	}
+	if got := ChunkSlice([]int{1, 2, 3}, 0); len(got) != 1 {
+		t.Errorf("expected a single chunk for size 0, got %v", got)
+	}
+}
+
+func TestProcessChunks(t *testing.T) {
+	data := make([]int, 1000)
+	for i := range data {
+		data[i] = i
+	}
+
+	sums, err := ProcessChunks(context.Background(), data, 0, 0, func(_ context.Context, chunk []int) (int, error) {
+		sum := 0
+		for _, n := range chunk {
+			sum += n
+		}
+		return sum, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	total := 0
+	for _, s := range sums {
+		total += s
+	}
+	if total != 999*1000/2 {
+		t.Errorf("expected sum %d, got %d", 999*1000/2, total)
+	}
+}
+
+func TestProcessChunks_Error(t *testing.T) {
+	errBad := errors.New("bad chunk")
+	_, err := ProcessChunks(context.Background(), make([]int, 100), 10, 4, func(_ context.Context, chunk []int) (int, error) {
+		return 0, errBad
+	})
+	if !errors.Is(err, errBad) {
+		t.Errorf("expected errBad, got %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Task is one named call run by Gather.
+type Task func(ctx context.Context) (any, error)
+
+// TaskError reports the failure of one Gather task.
+type TaskError struct {
+	Name string
+	Err  error
+}
+
+func (e *TaskError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Name, e.Err)
+}
+
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+type gatherConfig struct {
+	timeout  time.Duration
+	timeouts map[string]time.Duration
+}
+
+type GatherOption func(*gatherConfig)
+
+// WithTaskTimeout bounds how long each task may run.
+func WithTaskTimeout(d time.Duration) GatherOption {
+	return func(c *gatherConfig) {
+		c.timeout = d
+	}
+}
+
+// WithTimeoutFor bounds how long the named task may run, overriding
+// WithTaskTimeout.
+func WithTimeoutFor(name string, d time.Duration) GatherOption {
+	return func(c *gatherConfig) {
+		c.timeouts[name] = d
+	}
+}
+
+// Gather runs every task concurrently and returns the results of those that
+// succeeded, keyed by name. A failing task does not cancel the others, so
+// callers such as dashboard endpoints can render partial data; failures are
+// returned joined as *TaskError values.
+//
+//	results, err := parallel.Gather(ctx, map[string]parallel.Task{
+//		"user":   func(ctx context.Context) (any, error) { return users.Get(ctx, id) },
+//		"orders": func(ctx context.Context) (any, error) { return orders.List(ctx, id) },
+//	}, parallel.WithTaskTimeout(time.Second))
+func Gather(ctx context.Context, tasks map[string]Task, opts ...GatherOption) (map[string]any, error) {
+	cfg := &gatherConfig{timeouts: make(map[string]time.Duration)}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]any, len(tasks))
+		errs    []error
+	)
+	for name, task := range tasks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			taskCtx := ctx
+			timeout, ok := cfg.timeouts[name]
+			if !ok {
+				timeout = cfg.timeout
+			}
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				taskCtx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+
+			v, err := task(taskCtx)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, &TaskError{Name: name, Err: err})
+				return
+			}
+			results[name] = v
+		}()
+	}
+	wg.Wait()
+
+	return results, errors.Join(errs...)
+}
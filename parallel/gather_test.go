@@ -0,0 +1,36 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGather(t *testing.T) {
+	errDown := errors.New("down")
+	results, err := Gather(context.Background(), map[string]Task{
+		"user":   func(context.Context) (any, error) { return "alice", nil },
+		"count":  func(context.Context) (any, error) { return 42, nil },
+		"broken": func(context.Context) (any, error) { return nil, errDown },
+		"slow": func(ctx context.Context) (any, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}, WithTaskTimeout(time.Second), WithTimeoutFor("slow", 10*time.Millisecond))
+
+	if results["user"] != "alice" || results["count"] != 42 {
+		t.Errorf("unexpected results: %v", results)
+	}
+	if len(results) != 2 {
+		t.Errorf("expected only successful results, got %v", results)
+	}
+
+	if !errors.Is(err, errDown) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected joined task errors, got %v", err)
+	}
+	var te *TaskError
+	if !errors.As(err, &te) || (te.Name != "broken" && te.Name != "slow") {
+		t.Errorf("expected a TaskError naming the failed task, got %v", te)
+	}
+}
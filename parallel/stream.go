@@ -0,0 +1,67 @@
+package parallel
+
+import "context"
+
+// Result holds the outcome of processing a single item.
+type Result[R any] struct {
+	Index int
+	Value R
+	Err   error
+}
+
+// Stream runs fn over items with at most limit calls in flight and returns a
+// channel that yields one Result per item in submission order. Results are
+// delivered as soon as every earlier item has completed, so consumers can start
+// on the head of a large batch while the tail is still running.
+//
+// At most limit results are held in memory at once; a slow consumer applies
+// backpressure to the workers. The channel is closed once every item has been
+// delivered or ctx is cancelled.
+func Stream[T, R any](ctx context.Context, items []T, limit int, fn func(context.Context, T) (R, error)) <-chan Result[R] {
+	if limit < 1 {
+		limit = 1
+	}
+
+	out := make(chan Result[R])
+	// sem bounds the number of items that are running or waiting to be
+	// delivered; pending holds their result slots in submission order.
+	sem := make(chan struct{}, limit)
+	pending := make(chan chan Result[R], limit)
+
+	go func() {
+		defer close(pending)
+		for i, item := range items {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			slot := make(chan Result[R], 1)
+			pending <- slot
+			go func() {
+				v, err := fn(ctx, item)
+				slot <- Result[R]{Index: i, Value: v, Err: err}
+			}()
+		}
+	}()
+
+	go func() {
+		defer close(out)
+		for slot := range pending {
+			var r Result[R]
+			select {
+			case r = <-slot:
+			case <-ctx.Done():
+				return
+			}
+			<-sem
+			select {
+			case out <- r:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,99 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStream_PreservesOrder(t *testing.T) {
+	items := make([]int, 50)
+	for i := range items {
+		items[i] = i
+	}
+
+	results := Stream(context.Background(), items, 8, func(_ context.Context, n int) (int, error) {
+		time.Sleep(time.Duration(rand.IntN(3)) * time.Millisecond)
+		return n * 2, nil
+	})
+
+	i := 0
+	for r := range results {
+		if r.Index != i {
+			t.Fatalf("expected index %d, got %d", i, r.Index)
+		}
+		if r.Value != i*2 {
+			t.Errorf("expected value %d, got %d", i*2, r.Value)
+		}
+		i++
+	}
+	if i != len(items) {
+		t.Errorf("expected %d results, got %d", len(items), i)
+	}
+}
+
+func TestStream_ConcurrencyLimit(t *testing.T) {
+	limit := 3
+	var current, maxObserved int64
+
+	results := Stream(context.Background(), make([]struct{}, 20), limit, func(_ context.Context, _ struct{}) (struct{}, error) {
+		cur := atomic.AddInt64(&current, 1)
+		for {
+			old := atomic.LoadInt64(&maxObserved)
+			if cur <= old || atomic.CompareAndSwapInt64(&maxObserved, old, cur) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt64(&current, -1)
+		return struct{}{}, nil
+	})
+	for range results {
+	}
+
+	if maxObserved > int64(limit) {
+		t.Errorf("max concurrent calls = %d, exceeded limit of %d", maxObserved, limit)
+	}
+}
+
+func TestStream_ReportsErrors(t *testing.T) {
+	errOdd := errors.New("odd")
+	results := Stream(context.Background(), []int{0, 1, 2, 3}, 2, func(_ context.Context, n int) (int, error) {
+		if n%2 == 1 {
+			return 0, errOdd
+		}
+		return n, nil
+	})
+
+	for r := range results {
+		if r.Index%2 == 1 && !errors.Is(r.Err, errOdd) {
+			t.Errorf("item %d: expected errOdd, got %v", r.Index, r.Err)
+		}
+		if r.Index%2 == 0 && r.Err != nil {
+			t.Errorf("item %d: unexpected error %v", r.Index, r.Err)
+		}
+	}
+}
+
+func TestStream_ContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	results := Stream(ctx, make([]int, 1000), 4, func(_ context.Context, n int) (int, error) {
+		time.Sleep(time.Millisecond)
+		return n, nil
+	})
+
+	<-results
+	cancel()
+
+	received := 1
+	for range results {
+		received++
+	}
+	if received == 1000 {
+		t.Error("expected stream to stop early after cancel")
+	}
+}
@@ -0,0 +1,119 @@
+package pgclient
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"time"
+)
+
+// ResultCache stores encoded query results for QueryCache. Get reports
+// whether key was found. It is small enough to wrap any store; see the
+// NewQueryCache example for a go-redis adapter.
+type ResultCache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// QueryCache caches query results, typically in Redis, for read-heavy
+// endpoints on mostly-static tables. Entries live until their TTL expires or InvalidateKey
+// is called, typically after writing to the underlying tables.
+type QueryCache struct {
+	db     Client
+	cache  ResultCache
+	prefix string
+	logger *slog.Logger
+}
+
+type QueryCacheOption func(*QueryCache)
+
+// WithQueryCachePrefix sets the prefix prepended to every key (default:
+// "pgcache:").
+func WithQueryCachePrefix(prefix string) QueryCacheOption {
+	return func(c *QueryCache) {
+		c.prefix = prefix
+	}
+}
+
+// WithQueryCacheLogger sets the logger used for cache failures (default:
+// slog.Default()).
+func WithQueryCacheLogger(logger *slog.Logger) QueryCacheOption {
+	return func(c *QueryCache) {
+		c.logger = logger
+	}
+}
+
+// NewQueryCache creates a QueryCache reading from db and caching in cache.
+func NewQueryCache(db Client, cache ResultCache, opts ...QueryCacheOption) *QueryCache {
+	c := &QueryCache{
+		db:     db,
+		cache:  cache,
+		prefix: "pgcache:",
+		logger: slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// CachedQuery returns the rows cached under key, or runs query, scans each
+// row with scan and caches the result as JSON for ttl. T must round-trip
+// through encoding/json. Cache failures are logged and fall back to
+// Postgres, so the cache never makes a query fail.
+//
+//	users, err := pgclient.CachedQuery(ctx, cache, "users:active", time.Minute,
+//		func(rows *sql.Rows) (User, error) {
+//			var u User
+//			return u, rows.Scan(&u.ID, &u.Name)
+//		},
+//		"SELECT id, name FROM users WHERE active")
+func CachedQuery[T any](ctx context.Context, c *QueryCache, key string, ttl time.Duration,
+	scan func(*sql.Rows) (T, error), query string, args ...any) ([]T, error) {
+	key = c.prefix + key
+
+	if b, ok, err := c.cache.Get(ctx, key); err != nil {
+		c.logger.Warn("pgclient: query cache read failed", "key", key, "error", err)
+	} else if ok {
+		var cached []T
+		err := json.Unmarshal(b, &cached)
+		if err == nil {
+			return cached, nil
+		}
+		c.logger.Warn("pgclient: query cache decode failed", "key", key, "error", err)
+	}
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []T{}
+	for rows.Next() {
+		v, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(result)
+	if err == nil {
+		err = c.cache.Set(ctx, key, b, ttl)
+	}
+	if err != nil {
+		c.logger.Warn("pgclient: query cache write failed", "key", key, "error", err)
+	}
+	return result, nil
+}
+
+// InvalidateKey removes the cached result for key.
+func (c *QueryCache) InvalidateKey(ctx context.Context, key string) error {
+	return c.cache.Delete(ctx, c.prefix+key)
+}
@@ -0,0 +1,65 @@
+package pgclient_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	pgclient "github.com/bpurdy1/golang-packages/pg-client"
+	"github.com/redis/go-redis/v9"
+)
+
+// redisCache adapts a go-redis client to pgclient.ResultCache.
+type redisCache struct{ rdb *redis.Client }
+
+func (c redisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	b, err := c.rdb.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	return b, err == nil, err
+}
+
+func (c redisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.rdb.Set(ctx, key, value, ttl).Err()
+}
+
+func (c redisCache) Delete(ctx context.Context, key string) error {
+	return c.rdb.Del(ctx, key).Err()
+}
+
+func ExampleNewQueryCache() {
+	cfg, err := pgclient.NewConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+	db, err := pgclient.NewClient(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	cache := pgclient.NewQueryCache(db, redisCache{rdb})
+
+	type User struct {
+		ID   int64
+		Name string
+	}
+	ctx := context.Background()
+	users, err := pgclient.CachedQuery(ctx, cache, "users:active", time.Minute,
+		func(rows *sql.Rows) (User, error) {
+			var u User
+			return u, rows.Scan(&u.ID, &u.Name)
+		},
+		"SELECT id, name FROM users WHERE active")
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("%d active users", len(users))
+
+	// After changing users, drop the cached result.
+	if err := cache.InvalidateKey(ctx, "users:active"); err != nil {
+		log.Fatal(err)
+	}
+}
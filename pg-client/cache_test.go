@@ -0,0 +1,131 @@
+package pgclient
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type mapCache struct {
+	values map[string][]byte
+	ttls   map[string]time.Duration
+	err    error
+}
+
+func newMapCache() *mapCache {
+	return &mapCache{values: map[string][]byte{}, ttls: map[string]time.Duration{}}
+}
+
+func (c *mapCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	if c.err != nil {
+		return nil, false, c.err
+	}
+	b, ok := c.values[key]
+	return b, ok, nil
+}
+
+func (c *mapCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.values[key] = value
+	c.ttls[key] = ttl
+	return nil
+}
+
+func (c *mapCache) Delete(_ context.Context, key string) error {
+	delete(c.values, key)
+	return nil
+}
+
+type cachedUser struct {
+	ID   int
+	Name string
+}
+
+func scanCachedUser(rows *sql.Rows) (cachedUser, error) {
+	var u cachedUser
+	return u, rows.Scan(&u.ID, &u.Name)
+}
+
+const activeUsers = "SELECT id, name FROM users WHERE active"
+
+func newQueryCacheMock(t *testing.T, cache ResultCache) (*QueryCache, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		db.Close()
+	})
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewQueryCache(db, cache, WithQueryCacheLogger(logger)), mock
+}
+
+func TestCachedQuery(t *testing.T) {
+	cache := newMapCache()
+	qc, mock := newQueryCacheMock(t, cache)
+	ctx := context.Background()
+
+	mock.ExpectQuery(activeUsers).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+		AddRow(1, "alice").AddRow(2, "bob"))
+
+	// The second call is served from the cache; sqlmock fails on a second query.
+	for range 2 {
+		users, err := CachedQuery(ctx, qc, "users:active", time.Minute, scanCachedUser, activeUsers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(users) != 2 || users[1] != (cachedUser{2, "bob"}) {
+			t.Fatalf("users = %v", users)
+		}
+	}
+	if cache.ttls["pgcache:users:active"] != time.Minute {
+		t.Errorf("ttl = %v, want 1m", cache.ttls["pgcache:users:active"])
+	}
+
+	if err := qc.InvalidateKey(ctx, "users:active"); err != nil {
+		t.Fatal(err)
+	}
+	mock.ExpectQuery(activeUsers).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	users, err := CachedQuery(ctx, qc, "users:active", time.Minute, scanCachedUser, activeUsers)
+	if err != nil || users == nil || len(users) != 0 {
+		t.Errorf("CachedQuery after invalidation = %#v, %v; want an empty slice", users, err)
+	}
+}
+
+func TestCachedQuery_CacheFailureFallsBack(t *testing.T) {
+	cache := newMapCache()
+	cache.err = errors.New("redis down")
+	qc, mock := newQueryCacheMock(t, cache)
+
+	mock.ExpectQuery(activeUsers).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "alice"))
+	users, err := CachedQuery(context.Background(), qc, "users:active", time.Minute, scanCachedUser, activeUsers)
+	if err != nil || len(users) != 1 {
+		t.Errorf("CachedQuery = %v, %v; want the database result", users, err)
+	}
+}
+
+func TestCachedQuery_QueryErrorNotCached(t *testing.T) {
+	cache := newMapCache()
+	qc, mock := newQueryCacheMock(t, cache)
+
+	dbErr := errors.New("relation does not exist")
+	mock.ExpectQuery(activeUsers).WillReturnError(dbErr)
+	if _, err := CachedQuery(context.Background(), qc, "users:active", time.Minute, scanCachedUser, activeUsers); !errors.Is(err, dbErr) {
+		t.Errorf("CachedQuery error = %v, want %v", err, dbErr)
+	}
+	if len(cache.values) != 0 {
+		t.Error("failed query was cached")
+	}
+}
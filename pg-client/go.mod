@@ -3,6 +3,16 @@ module github.com/bpurdy1/golang-packages/pg-client
 go 1.25.6
 
 require (
-	github.com/caarlos0/env/v11 v11.3.1
+	github.com/DATA-DOG/go-sqlmock v1.5.2
+	github.com/bpurdy1/golang-packages/envparse v0.0.0-00010101000000-000000000000
 	github.com/lib/pq v1.10.9
+	github.com/redis/go-redis/v9 v9.17.2
 )
+
+require (
+	github.com/caarlos0/env/v11 v11.3.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+)
+
+replace github.com/bpurdy1/golang-packages/envparse => ../envparse
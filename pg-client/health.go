@@ -0,0 +1,85 @@
+package pgclient
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+var (
+	healthStatus   = read("queries/health_status.sql")
+	healthReplicas = read("queries/health_replicas.sql")
+)
+
+// Healther is implemented by clients that can report their own health, so
+// readiness probes can check every dependency the same way. The Client
+// returned by NewClient implements it:
+//
+//	if h, ok := db.(pgclient.Healther); ok {
+//		health, err := h.HealthCheck(ctx)
+//	}
+type Healther interface {
+	HealthCheck(ctx context.Context) (*Health, error)
+}
+
+// Health is a point-in-time report on the database connection.
+type Health struct {
+	// Latency is the round trip time of a ping.
+	Latency       time.Duration
+	ServerVersion string
+	Pool          sql.DBStats
+	// InRecovery is true when connected to a standby; ReplayLag is then the
+	// time since the last replayed transaction.
+	InRecovery bool
+	ReplayLag  time.Duration
+	// Replicas lists the standbys streaming from this server.
+	Replicas []ReplicaStatus
+}
+
+// ReplicaStatus describes one standby as seen from the primary.
+type ReplicaStatus struct {
+	Name      string
+	Addr      string
+	State     string
+	ReplayLag time.Duration
+}
+
+var _ Healther = (*PostgresClient)(nil)
+
+// HealthCheck pings the database and gathers server and replication details.
+// Pool statistics are filled in even when the database is unreachable.
+func (c *PostgresClient) HealthCheck(ctx context.Context) (*Health, error) {
+	h := &Health{Pool: c.Stats()}
+
+	start := time.Now()
+	if err := c.PingContext(ctx); err != nil {
+		return h, err
+	}
+	h.Latency = time.Since(start)
+
+	var replayLag float64
+	if err := c.QueryRowContext(ctx, healthStatus).Scan(&h.ServerVersion, &h.InRecovery, &replayLag); err != nil {
+		return h, err
+	}
+	h.ReplayLag = seconds(replayLag)
+
+	rows, err := c.QueryContext(ctx, healthReplicas)
+	if err != nil {
+		return h, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var r ReplicaStatus
+		var lag float64
+		if err := rows.Scan(&r.Name, &r.Addr, &r.State, &lag); err != nil {
+			return h, err
+		}
+		r.ReplayLag = seconds(lag)
+		h.Replicas = append(h.Replicas, r)
+	}
+	return h, rows.Err()
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
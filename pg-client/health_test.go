@@ -0,0 +1,117 @@
+package pgclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func newHealthMock(t *testing.T) (*PostgresClient, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New(
+		sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual),
+		sqlmock.MonitorPingsOption(true),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		db.Close()
+	})
+	return &PostgresClient{db}, mock
+}
+
+var (
+	statusColumns  = []string{"server_version", "pg_is_in_recovery", "replay_lag"}
+	replicaColumns = []string{"application_name", "client_addr", "state", "replay_lag"}
+)
+
+func TestHealthCheck_Primary(t *testing.T) {
+	c, mock := newHealthMock(t)
+
+	mock.ExpectPing()
+	mock.ExpectQuery(healthStatus).WillReturnRows(
+		sqlmock.NewRows(statusColumns).AddRow("16.4", false, 0.0))
+	mock.ExpectQuery(healthReplicas).WillReturnRows(
+		sqlmock.NewRows(replicaColumns).
+			AddRow("replica-a", "10.0.0.2", "streaming", 0.25).
+			AddRow("replica-b", "10.0.0.3", "catchup", 12.5))
+
+	h, err := c.HealthCheck(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.ServerVersion != "16.4" || h.InRecovery || h.ReplayLag != 0 {
+		t.Errorf("status = %q, in recovery %v, lag %v", h.ServerVersion, h.InRecovery, h.ReplayLag)
+	}
+	want := []ReplicaStatus{
+		{Name: "replica-a", Addr: "10.0.0.2", State: "streaming", ReplayLag: 250 * time.Millisecond},
+		{Name: "replica-b", Addr: "10.0.0.3", State: "catchup", ReplayLag: 12500 * time.Millisecond},
+	}
+	if len(h.Replicas) != len(want) {
+		t.Fatalf("Replicas = %+v, want %+v", h.Replicas, want)
+	}
+	for i := range want {
+		if h.Replicas[i] != want[i] {
+			t.Errorf("Replicas[%d] = %+v, want %+v", i, h.Replicas[i], want[i])
+		}
+	}
+}
+
+func TestHealthCheck_Replica(t *testing.T) {
+	c, mock := newHealthMock(t)
+
+	mock.ExpectPing()
+	mock.ExpectQuery(healthStatus).WillReturnRows(
+		sqlmock.NewRows(statusColumns).AddRow("16.4", true, 3.5))
+	mock.ExpectQuery(healthReplicas).WillReturnRows(sqlmock.NewRows(replicaColumns))
+
+	h, err := c.HealthCheck(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !h.InRecovery || h.ReplayLag != 3500*time.Millisecond {
+		t.Errorf("in recovery %v, lag %v, want true, 3.5s", h.InRecovery, h.ReplayLag)
+	}
+	if len(h.Replicas) != 0 {
+		t.Errorf("Replicas = %+v, want none", h.Replicas)
+	}
+}
+
+func TestHealthCheck_PingFails(t *testing.T) {
+	c, mock := newHealthMock(t)
+	errDown := errors.New("connection refused")
+
+	mock.ExpectPing().WillReturnError(errDown)
+
+	h, err := c.HealthCheck(context.Background())
+	if !errors.Is(err, errDown) {
+		t.Fatalf("HealthCheck = %v, want %v", err, errDown)
+	}
+	if h == nil {
+		t.Fatal("HealthCheck returned no report")
+	}
+	if h.Latency != 0 || h.ServerVersion != "" {
+		t.Errorf("report filled in after failed ping: %+v", h)
+	}
+}
+
+func TestHealthCheck_ReplicaScanError(t *testing.T) {
+	c, mock := newHealthMock(t)
+
+	mock.ExpectPing()
+	mock.ExpectQuery(healthStatus).WillReturnRows(
+		sqlmock.NewRows(statusColumns).AddRow("16.4", false, 0.0))
+	mock.ExpectQuery(healthReplicas).WillReturnRows(
+		sqlmock.NewRows(replicaColumns).AddRow("replica-a", "10.0.0.2", "streaming", "not a number"))
+
+	if _, err := c.HealthCheck(context.Background()); err == nil {
+		t.Error("HealthCheck succeeded with an unreadable replica row")
+	}
+}
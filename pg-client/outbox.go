@@ -0,0 +1,227 @@
+package pgclient
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// OutboxSchema creates the outbox table used by WriteOutbox and Relay.
+var OutboxSchema = read("queries/outbox_schema.sql")
+
+var (
+	outboxInsert    = read("queries/outbox_insert.sql")
+	outboxClaim     = read("queries/outbox_claim.sql")
+	outboxPublished = read("queries/outbox_published.sql")
+	outboxFailed    = read("queries/outbox_failed.sql")
+)
+
+// OutboxEvent is a message to publish once the surrounding transaction commits.
+type OutboxEvent struct {
+	Topic   string
+	Payload []byte
+}
+
+// WriteOutbox stores event in the outbox as part of tx, so it is published if
+// and only if tx commits.
+func WriteOutbox(ctx context.Context, tx *sql.Tx, event OutboxEvent) error {
+	_, err := tx.ExecContext(ctx, outboxInsert, event.Topic, event.Payload)
+	return err
+}
+
+// Publisher sends a payload to a topic. It is satisfied by the queue package's
+// SQS and NATS implementations.
+type Publisher interface {
+	Publish(ctx context.Context, topic string, data []byte) error
+}
+
+// RelayDB is the database interface used by Relay. Client, *sql.DB and
+// *sql.Conn implement it.
+type RelayDB interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// Relay polls the outbox and publishes pending events. Events are marked as
+// published only after Publish succeeds, giving at-least-once delivery.
+//
+// Failed publishes are retried with exponential backoff. After the maximum
+// number of attempts an event is dead-lettered: dead_at is set and the relay
+// skips it. Dead events can be retried by clearing dead_at.
+type Relay struct {
+	db          RelayDB
+	publisher   Publisher
+	batchSize   int
+	interval    time.Duration
+	lease       time.Duration
+	maxAttempts int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	logger      *slog.Logger
+}
+
+type RelayOption func(*Relay)
+
+// WithRelayBatchSize sets the maximum number of events published per poll (default: 100).
+func WithRelayBatchSize(n int) RelayOption {
+	return func(r *Relay) {
+		r.batchSize = n
+	}
+}
+
+// WithRelayInterval sets the delay between polls when the outbox is drained (default: 1s).
+func WithRelayInterval(d time.Duration) RelayOption {
+	return func(r *Relay) {
+		r.interval = d
+	}
+}
+
+// WithRelayLease sets how long a claimed batch is hidden from other relays
+// while it is published (default: 1m). Events still unmarked after the lease,
+// e.g. because the relay crashed, are published again.
+func WithRelayLease(d time.Duration) RelayOption {
+	return func(r *Relay) {
+		r.lease = d
+	}
+}
+
+// WithRelayMaxAttempts sets how many times an event is published before it
+// is dead-lettered (default: 10); zero retries forever.
+func WithRelayMaxAttempts(n int) RelayOption {
+	return func(r *Relay) {
+		r.maxAttempts = n
+	}
+}
+
+// WithRelayBackoff sets the delay before retrying a failed event, doubling
+// from minDelay after each attempt up to maxDelay (default: 1s to 5m).
+func WithRelayBackoff(minDelay, maxDelay time.Duration) RelayOption {
+	return func(r *Relay) {
+		r.minBackoff = minDelay
+		r.maxBackoff = maxDelay
+	}
+}
+
+// WithRelayLogger sets the logger used to report poll failures (default: slog.Default()).
+func WithRelayLogger(logger *slog.Logger) RelayOption {
+	return func(r *Relay) {
+		r.logger = logger
+	}
+}
+
+// NewRelay creates a Relay publishing outbox events from db through publisher.
+func NewRelay(db RelayDB, publisher Publisher, opts ...RelayOption) *Relay {
+	r := &Relay{
+		db:          db,
+		publisher:   publisher,
+		batchSize:   100,
+		interval:    time.Second,
+		lease:       time.Minute,
+		maxAttempts: 10,
+		minBackoff:  time.Second,
+		maxBackoff:  5 * time.Minute,
+		logger:      slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Run polls until ctx is cancelled. Poll failures are logged and retried on
+// the next tick.
+func (r *Relay) Run(ctx context.Context) error {
+	for {
+		n, err := r.Poll(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.logger.Error("outbox relay poll failed", "error", err)
+		}
+		if err == nil && n == r.batchSize {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(r.interval):
+		}
+	}
+}
+
+type outboxRow struct {
+	id       int64
+	attempts int
+	OutboxEvent
+}
+
+// Poll publishes one batch of due events and returns how many it claimed.
+// The batch is claimed by pushing next_attempt_at past the lease in a single
+// statement, so no row locks are held while publishing and several relays
+// can run side by side.
+func (r *Relay) Poll(ctx context.Context) (int, error) {
+	events, err := r.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, e := range events {
+		pubErr := r.publisher.Publish(ctx, e.Topic, e.Payload)
+		if pubErr == nil {
+			_, err = r.db.ExecContext(ctx, outboxPublished, e.id)
+		} else {
+			err = r.fail(ctx, e, pubErr)
+		}
+		if err != nil {
+			return len(events), err
+		}
+	}
+	return len(events), nil
+}
+
+func (r *Relay) claim(ctx context.Context) ([]outboxRow, error) {
+	rows, err := r.db.QueryContext(ctx, outboxClaim, r.batchSize, r.lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []outboxRow
+	for rows.Next() {
+		var e outboxRow
+		if err := rows.Scan(&e.id, &e.Topic, &e.Payload, &e.attempts); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not preserve the ORDER BY of the subquery.
+	slices.SortFunc(events, func(a, b outboxRow) int { return cmp.Compare(a.id, b.id) })
+	return events, nil
+}
+
+// fail records a failed publish, scheduling a retry or dead-lettering e.
+func (r *Relay) fail(ctx context.Context, e outboxRow, pubErr error) error {
+	attempts := e.attempts + 1
+	dead := r.maxAttempts > 0 && attempts >= r.maxAttempts
+	if dead {
+		r.logger.Warn("outbox event dead-lettered",
+			"id", e.id, "topic", e.Topic, "attempts", attempts, "error", pubErr)
+	}
+	_, err := r.db.ExecContext(ctx, outboxFailed, e.id, pubErr.Error(), r.backoff(attempts).Seconds(), dead)
+	return err
+}
+
+// backoff returns the delay before the next attempt of an event that has
+// failed attempts times.
+func (r *Relay) backoff(attempts int) time.Duration {
+	d := r.minBackoff
+	for i := 1; i < attempts && d < r.maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, r.maxBackoff)
+}
@@ -0,0 +1,130 @@
+package pgclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type publishedEvent struct {
+	topic   string
+	payload string
+}
+
+type fakePublisher struct {
+	published []publishedEvent
+	fail      map[string]error
+}
+
+func (p *fakePublisher) Publish(_ context.Context, topic string, data []byte) error {
+	if err := p.fail[topic]; err != nil {
+		return err
+	}
+	p.published = append(p.published, publishedEvent{topic, string(data)})
+	return nil
+}
+
+func newRelayMock(t *testing.T, publisher Publisher, opts ...RelayOption) (*Relay, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		db.Close()
+	})
+	opts = append([]RelayOption{WithRelayLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))}, opts...)
+	return NewRelay(db, publisher, opts...), mock
+}
+
+func claimRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "topic", "payload", "attempts"})
+}
+
+// The claim is a single statement, so no transaction is open while
+// publishing; sqlmock fails the relay tests on an unexpected Begin.
+func TestRelay_PollPublishesInOrder(t *testing.T) {
+	publisher := &fakePublisher{}
+	relay, mock := newRelayMock(t, publisher, WithRelayBatchSize(10), WithRelayLease(30*time.Second))
+
+	mock.ExpectQuery(outboxClaim).WithArgs(10, 30.0).WillReturnRows(claimRows().
+		AddRow(2, "users.updated", []byte("b"), 0).
+		AddRow(1, "users.created", []byte("a"), 0))
+	mock.ExpectExec(outboxPublished).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(outboxPublished).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	n, err := relay.Poll(context.Background())
+	if err != nil || n != 2 {
+		t.Fatalf("Poll = %d, %v; want 2, nil", n, err)
+	}
+	want := []publishedEvent{{"users.created", "a"}, {"users.updated", "b"}}
+	if len(publisher.published) != 2 || publisher.published[0] != want[0] || publisher.published[1] != want[1] {
+		t.Errorf("published %v, want %v", publisher.published, want)
+	}
+}
+
+func TestRelay_PollRetriesAndDeadLetters(t *testing.T) {
+	publisher := &fakePublisher{fail: map[string]error{"broken": errors.New("broker down")}}
+	relay, mock := newRelayMock(t, publisher,
+		WithRelayMaxAttempts(3), WithRelayBackoff(time.Second, time.Minute))
+
+	mock.ExpectQuery(outboxClaim).WithArgs(100, 60.0).WillReturnRows(claimRows().
+		AddRow(1, "broken", []byte("a"), 0).
+		AddRow(2, "broken", []byte("b"), 2).
+		AddRow(3, "ok", []byte("c"), 1))
+	// First failure: retried after the minimum backoff.
+	mock.ExpectExec(outboxFailed).WithArgs(1, "broker down", 1.0, false).WillReturnResult(sqlmock.NewResult(0, 1))
+	// Third failure reaches the maximum attempts.
+	mock.ExpectExec(outboxFailed).WithArgs(2, "broker down", 4.0, true).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(outboxPublished).WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	n, err := relay.Poll(context.Background())
+	if err != nil || n != 3 {
+		t.Fatalf("Poll = %d, %v; want 3, nil", n, err)
+	}
+	if len(publisher.published) != 1 || publisher.published[0].topic != "ok" {
+		t.Errorf("published %v, want only the ok event", publisher.published)
+	}
+}
+
+func TestRelay_PollErrors(t *testing.T) {
+	relay, mock := newRelayMock(t, &fakePublisher{})
+
+	dbErr := errors.New("connection reset")
+	mock.ExpectQuery(outboxClaim).WillReturnError(dbErr)
+	if _, err := relay.Poll(context.Background()); !errors.Is(err, dbErr) {
+		t.Errorf("Poll claim error = %v, want %v", err, dbErr)
+	}
+
+	mock.ExpectQuery(outboxClaim).WillReturnRows(claimRows().AddRow(1, "users.created", []byte("a"), 0))
+	mock.ExpectExec(outboxPublished).WithArgs(1).WillReturnError(dbErr)
+	if _, err := relay.Poll(context.Background()); !errors.Is(err, dbErr) {
+		t.Errorf("Poll mark error = %v, want %v", err, dbErr)
+	}
+}
+
+func TestRelay_Backoff(t *testing.T) {
+	relay := NewRelay(nil, nil, WithRelayBackoff(time.Second, 10*time.Second))
+
+	tests := map[int]time.Duration{
+		1:   time.Second,
+		2:   2 * time.Second,
+		3:   4 * time.Second,
+		4:   8 * time.Second,
+		5:   10 * time.Second,
+		100: 10 * time.Second,
+	}
+	for attempts, want := range tests {
+		if got := relay.backoff(attempts); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
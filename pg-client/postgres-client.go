@@ -7,7 +7,7 @@ import (
 	"embed"
 	"fmt"
 
-	"github.com/caarlos0/env/v11"
+	"github.com/bpurdy1/golang-packages/envparse"
 	_ "github.com/lib/pq" // Postgres driver
 )
 
@@ -38,14 +38,14 @@ type Config struct {
 	Host    string `env:"DB_HOST" envDefault:"localhost"`
 	Port    int    `env:"DB_PORT" envDefault:"5432"`
 	User    string `env:"DB_USER" envDefault:"postgres"`
-	Pass    string `env:"DB_PASS"`
+	Pass    string `env:"DB_PASS" sensitive:"true"`
 	Name    string `env:"DB_NAME"`
 	SSLMode string `env:"DB_SSLMODE" envDefault:"disable"`
 }
 
 func NewConfig() (*Config, error) {
 	cfg := &Config{}
-	if err := env.Parse(cfg); err != nil {
+	if err := envparse.Parse(cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse pg config: %w", err)
 	}
 	return cfg, nil
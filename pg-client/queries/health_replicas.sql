@@ -0,0 +1,6 @@
+SELECT application_name,
+       COALESCE(host(client_addr), ''),
+       state,
+       COALESCE(EXTRACT(EPOCH FROM replay_lag), 0)
+FROM pg_stat_replication
+ORDER BY application_name
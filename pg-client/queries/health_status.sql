@@ -0,0 +1,3 @@
+SELECT current_setting('server_version'),
+       pg_is_in_recovery(),
+       COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
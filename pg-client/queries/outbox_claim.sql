@@ -0,0 +1,11 @@
+UPDATE outbox
+SET next_attempt_at = now() + make_interval(secs => $2)
+WHERE id IN (
+    SELECT id
+    FROM outbox
+    WHERE published_at IS NULL AND dead_at IS NULL AND next_attempt_at <= now()
+    ORDER BY id
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, topic, payload, attempts
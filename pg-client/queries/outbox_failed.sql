@@ -0,0 +1,6 @@
+UPDATE outbox
+SET attempts = attempts + 1,
+    last_error = $2,
+    next_attempt_at = now() + make_interval(secs => $3),
+    dead_at = CASE WHEN $4::boolean THEN now() END
+WHERE id = $1
@@ -0,0 +1 @@
+INSERT INTO outbox (topic, payload) VALUES ($1, $2)
@@ -0,0 +1 @@
+UPDATE outbox SET published_at = now(), attempts = attempts + 1, last_error = NULL WHERE id = $1
@@ -0,0 +1,198 @@
+package pgclient
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// setLocal changes a setting for the rest of the current transaction.
+var setLocal = read("queries/set_local.sql")
+
+// DBTX is the database interface used by sqlc-generated code for
+// database/sql. Both *PostgresClient and *sql.Tx satisfy it, so generated
+// Queries can run against the pool or a transaction.
+type DBTX interface {
+	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
+	PrepareContext(context.Context, string) (*sql.Stmt, error)
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
+	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
+}
+
+// TxBeginner starts transactions. *sql.DB and *PostgresClient, the Client
+// returned by NewClient, implement it.
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+var (
+	_ DBTX       = (*PostgresClient)(nil)
+	_ DBTX       = (*sql.Tx)(nil)
+	_ TxBeginner = (*PostgresClient)(nil)
+)
+
+type txOptions struct {
+	sqlOpts          *sql.TxOptions
+	statementTimeout time.Duration
+	lockTimeout      time.Duration
+}
+
+type TxOption func(*txOptions)
+
+// WithTxOptions sets the isolation level and read-only flag of the
+// transaction.
+func WithTxOptions(opts *sql.TxOptions) TxOption {
+	return func(o *txOptions) {
+		o.sqlOpts = opts
+	}
+}
+
+// WithStatementTimeout makes Postgres cancel any statement in the transaction
+// that runs longer than d.
+func WithStatementTimeout(d time.Duration) TxOption {
+	return func(o *txOptions) {
+		o.statementTimeout = d
+	}
+}
+
+// WithLockTimeout makes Postgres fail any statement in the transaction that
+// waits longer than d to acquire a lock, instead of queueing behind a
+// long-running writer.
+func WithLockTimeout(d time.Duration) TxOption {
+	return func(o *txOptions) {
+		o.lockTimeout = d
+	}
+}
+
+// statementTimeoutFor returns the configured statement timeout capped by the
+// context deadline, so the server stops work the caller has given up on even
+// if the client's cancel request is lost.
+func (o *txOptions) statementTimeoutFor(ctx context.Context) time.Duration {
+	d := o.statementTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := max(time.Until(deadline), time.Millisecond); d <= 0 || remaining < d {
+			d = remaining
+		}
+	}
+	return d
+}
+
+// WithTx runs fn in a transaction. The transaction is committed if fn returns
+// nil and rolled back if it returns an error or panics. db is typically a
+// *sql.DB or the *PostgresClient returned by NewClient.
+//
+// When ctx has a deadline, the transaction's statement_timeout is set to the
+// time remaining. WithStatementTimeout and WithLockTimeout set timeouts
+// scoped to the transaction, leaving the pooled connection unchanged. Exec
+// and Query apply the same options to a single statement.
+func WithTx(ctx context.Context, db TxBeginner, fn func(tx *sql.Tx) error, opts ...TxOption) error {
+	var o txOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	tx, err := db.BeginTx(ctx, o.sqlOpts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	timeouts := []struct {
+		name string
+		d    time.Duration
+	}{
+		{"statement_timeout", o.statementTimeoutFor(ctx)},
+		{"lock_timeout", o.lockTimeout},
+	}
+	for _, t := range timeouts {
+		if t.d <= 0 {
+			continue
+		}
+		// Postgres reads a unitless value as milliseconds, and 0 disables the
+		// timeout, so round up.
+		ms := max(t.d.Milliseconds(), 1)
+		if _, err := tx.ExecContext(ctx, setLocal, t.name, strconv.FormatInt(ms, 10)); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("set %s: %w", t.name, err)
+		}
+	}
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("tx error: %w, rollback error: %v", err, rbErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// WithTxQuerier runs fn in a transaction like WithTx, passing it a querier
+// bound to the transaction. newQuerier is typically the New function of a
+// sqlc-generated package.
+//
+//	err := pgclient.WithTxQuerier(ctx, db, authdb.New, func(q *authdb.Queries) error {
+//		user, err := q.CreateUser(ctx, params)
+//		if err != nil {
+//			return err
+//		}
+//		return q.CreateSession(ctx, user.ID)
+//	})
+func WithTxQuerier[Q any](ctx context.Context, db TxBeginner, newQuerier func(DBTX) Q, fn func(q Q) error, opts ...TxOption) error {
+	return WithTx(ctx, db, func(tx *sql.Tx) error {
+		return fn(newQuerier(tx))
+	}, opts...)
+}
+
+// Exec runs a single statement with the timeouts set by opts, such as
+// WithStatementTimeout. The statement runs in its own transaction so the
+// timeouts are scoped to it; with no options it is the same as
+// db.ExecContext except for the extra round trips.
+//
+//	res, err := pgclient.Exec(ctx, db, []pgclient.TxOption{pgclient.WithStatementTimeout(2 * time.Second)},
+//		"DELETE FROM sessions WHERE expires_at < now()")
+func Exec(ctx context.Context, db TxBeginner, opts []TxOption, query string, args ...any) (sql.Result, error) {
+	var res sql.Result
+	err := WithTx(ctx, db, func(tx *sql.Tx) error {
+		var err error
+		res, err = tx.ExecContext(ctx, query, args...)
+		return err
+	}, opts...)
+	return res, err
+}
+
+// Query runs a single query with the timeouts set by opts like Exec, and
+// returns its rows scanned with scan.
+//
+//	users, err := pgclient.Query(ctx, db, []pgclient.TxOption{pgclient.WithStatementTimeout(time.Second)},
+//		func(rows *sql.Rows) (User, error) {
+//			var u User
+//			return u, rows.Scan(&u.ID, &u.Name)
+//		},
+//		"SELECT id, name FROM users WHERE team_id = $1", teamID)
+func Query[T any](ctx context.Context, db TxBeginner, opts []TxOption, scan func(*sql.Rows) (T, error), query string, args ...any) ([]T, error) {
+	var result []T
+	err := WithTx(ctx, db, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			v, err := scan(rows)
+			if err != nil {
+				return err
+			}
+			result = append(result, v)
+		}
+		return rows.Err()
+	}, opts...)
+	return result, err
+}
@@ -0,0 +1,102 @@
+package pgclient
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func newTxMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		db.Close()
+	})
+	return db, mock
+}
+
+func TestExec_SetsTimeouts(t *testing.T) {
+	db, mock := newTxMock(t)
+	const query = "DELETE FROM sessions WHERE expires_at < now()"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(setLocal).WithArgs("statement_timeout", "2000").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(setLocal).WithArgs("lock_timeout", "1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	res, err := Exec(context.Background(), db,
+		[]TxOption{WithStatementTimeout(2 * time.Second), WithLockTimeout(time.Microsecond)}, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := res.RowsAffected(); n != 3 {
+		t.Errorf("RowsAffected = %d, want 3", n)
+	}
+}
+
+func TestExec_ContextDeadlineCapsTimeout(t *testing.T) {
+	db, mock := newTxMock(t)
+	const query = "UPDATE users SET active = false"
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(setLocal).WithArgs("statement_timeout", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(query).WillReturnError(errors.New("canceling statement due to statement timeout"))
+	mock.ExpectRollback()
+
+	if _, err := Exec(ctx, db, []TxOption{WithStatementTimeout(time.Hour)}, query); err == nil {
+		t.Fatal("expected the statement error")
+	}
+
+	var o txOptions
+	WithStatementTimeout(time.Hour)(&o)
+	if d := o.statementTimeoutFor(ctx); d > time.Minute || d <= 0 {
+		t.Errorf("statement timeout = %v, want the minute left on ctx", d)
+	}
+}
+
+func TestQuery_ScansRows(t *testing.T) {
+	db, mock := newTxMock(t)
+	const query = "SELECT id, name FROM users WHERE team_id = $1"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(setLocal).WithArgs("statement_timeout", "1000").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(query).WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+		AddRow(1, "alice").AddRow(2, "bob"))
+	mock.ExpectCommit()
+
+	users, err := Query(context.Background(), db, []TxOption{WithStatementTimeout(time.Second)},
+		scanCachedUser, query, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || users[0] != (cachedUser{1, "alice"}) {
+		t.Errorf("users = %v", users)
+	}
+}
+
+func TestWithTx_RollsBackOnError(t *testing.T) {
+	db, mock := newTxMock(t)
+	fnErr := errors.New("insert failed")
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	err := WithTx(context.Background(), db, func(*sql.Tx) error { return fnErr })
+	if !errors.Is(err, fnErr) {
+		t.Errorf("WithTx = %v, want %v", err, fnErr)
+	}
+}
@@ -0,0 +1,104 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	natsclient "github.com/bpurdy1/golang-packages/nats-client"
+	"github.com/nats-io/nats.go"
+)
+
+// NATS is a Queue backed by JetStream. Topics are subjects, and each topic
+// must be covered by an existing stream.
+//
+// Messages are acked explicitly: a nacked message, or one not acked within
+// the consumer's ack wait, is redelivered.
+type NATS struct {
+	js    nats.JetStreamContext
+	group string
+}
+
+// NewNATS creates a Queue on top of a JetStream context. When group is set,
+// the group's consumers of a topic share a durable consumer named after the
+// group and topic, so each message is handled by one member and a group that
+// restarts resumes where it stopped. A group new to a topic starts at the
+// beginning of the stream. Without a group every consumer receives every
+// message published after it subscribed.
+func NewNATS(js nats.JetStreamContext, group string) *NATS {
+	return &NATS{
+		js:    js,
+		group: group,
+	}
+}
+
+// Publish sends data to the subject topic and waits for the stream to store
+// it.
+func (q *NATS) Publish(ctx context.Context, topic string, data []byte) error {
+	_, err := q.js.Publish(topic, data, nats.Context(ctx))
+	return err
+}
+
+// Consume subscribes to topic and runs handler for each message until ctx is
+// cancelled.
+func (q *NATS) Consume(ctx context.Context, topic string, handler Handler) error {
+	cb := func(m *nats.Msg) {
+		msg := &Message{
+			Topic: m.Subject,
+			Data:  m.Data,
+			ack: func(ctx context.Context) error {
+				return m.AckSync(nats.Context(ctx))
+			},
+			nack: func(context.Context) error {
+				return m.Nak()
+			},
+		}
+		if meta, err := m.Metadata(); err == nil {
+			msg.ID = meta.Stream + ":" + strconv.FormatUint(meta.Sequence.Stream, 10)
+		}
+		_ = handle(ctx, handler, msg)
+	}
+
+	var sub *nats.Subscription
+	var err error
+	if q.group != "" {
+		durable := natsclient.DurableName(q.group, topic)
+		var stream string
+		if stream, err = q.groupConsumer(topic, durable); err != nil {
+			return err
+		}
+		sub, err = q.js.QueueSubscribe(topic, q.group, cb, nats.Bind(stream, durable), nats.ManualAck())
+	} else {
+		sub, err = q.js.Subscribe(topic, cb, nats.DeliverNew(), nats.ManualAck())
+	}
+	if err != nil {
+		return err
+	}
+
+	<-ctx.Done()
+	return sub.Unsubscribe()
+}
+
+// groupConsumer creates the group's durable consumer for topic on the stream
+// holding topic unless it exists, and returns the stream name. The consumer
+// is created here rather than by QueueSubscribe, which would delete it as
+// soon as any member unsubscribes.
+func (q *NATS) groupConsumer(topic, durable string) (string, error) {
+	stream, err := q.js.StreamNameBySubject(topic)
+	if err != nil {
+		return "", err
+	}
+	if _, err := q.js.ConsumerInfo(stream, durable); !errors.Is(err, nats.ErrConsumerNotFound) {
+		return stream, err
+	}
+	// Members may race to create the consumer; the config is deterministic,
+	// so the server accepts every identical request.
+	_, err = q.js.AddConsumer(stream, &nats.ConsumerConfig{
+		Durable:        durable,
+		DeliverGroup:   q.group,
+		DeliverSubject: "_QUEUE." + stream + "." + durable,
+		FilterSubject:  topic,
+		AckPolicy:      nats.AckExplicitPolicy,
+	})
+	return stream, err
+}
@@ -0,0 +1,153 @@
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	natsclient "github.com/bpurdy1/golang-packages/nats-client"
+	"github.com/bpurdy1/golang-packages/queue"
+	"github.com/nats-io/nats-server/v2/server"
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newJetStream starts an in-process server with an "ORDERS" stream covering
+// "orders.>".
+func newJetStream(t *testing.T) nats.JetStreamContext {
+	t.Helper()
+	opts := natsserver.DefaultTestOptions
+	opts.Port = server.RANDOM_PORT
+	opts.JetStream = true
+	opts.StoreDir = t.TempDir()
+	srv := natsserver.RunServer(&opts)
+	t.Cleanup(srv.Shutdown)
+
+	nc, err := nats.Connect(srv.ClientURL())
+	require.NoError(t, err)
+	t.Cleanup(nc.Close)
+	js, err := nc.JetStream()
+	require.NoError(t, err)
+	_, err = js.AddStream(&nats.StreamConfig{Name: "ORDERS", Subjects: []string{"orders.>"}})
+	require.NoError(t, err)
+	return js
+}
+
+// collector records message payloads and fails the first attempt of any
+// payload listed in failOnce.
+type collector struct {
+	mu       sync.Mutex
+	got      []string
+	failOnce map[string]bool
+}
+
+func (c *collector) handle(_ context.Context, msg *queue.Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.got = append(c.got, string(msg.Data))
+	if c.failOnce[string(msg.Data)] {
+		delete(c.failOnce, string(msg.Data))
+		return errors.New("try again")
+	}
+	return nil
+}
+
+func (c *collector) received() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.got...)
+}
+
+// consume runs q.Consume in the background until the test ends.
+func consume(t *testing.T, q *queue.NATS, topic string, c *collector) context.CancelFunc {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- q.Consume(ctx, topic, c.handle) }()
+	stop := func() {
+		cancel()
+		assert.NoError(t, <-done)
+	}
+	var once sync.Once
+	t.Cleanup(func() { once.Do(stop) })
+	return func() { once.Do(stop) }
+}
+
+func TestNATS_GroupConsumersPerTopic(t *testing.T) {
+	js := newJetStream(t)
+	q := queue.NewNATS(js, "workers")
+	ctx := context.Background()
+
+	require.NoError(t, q.Publish(ctx, "orders.created", []byte("created-1")))
+	require.NoError(t, q.Publish(ctx, "orders.deleted", []byte("deleted-1")))
+
+	// The same group consumes two topics of one stream; each gets its own
+	// durable consumer and only its own messages.
+	var created, deleted collector
+	consume(t, q, "orders.created", &created)
+	consume(t, q, "orders.deleted", &deleted)
+
+	assert.Eventually(t, func() bool {
+		return len(created.received()) == 1 && len(deleted.received()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"created-1"}, created.received())
+	assert.Equal(t, []string{"deleted-1"}, deleted.received())
+
+	for _, topic := range []string{"orders.created", "orders.deleted"} {
+		info, err := js.ConsumerInfo("ORDERS", natsclient.DurableName("workers", topic))
+		require.NoError(t, err, topic)
+		assert.Equal(t, topic, info.Config.FilterSubject)
+	}
+}
+
+func TestNATS_GroupResumes(t *testing.T) {
+	js := newJetStream(t)
+	q := queue.NewNATS(js, "workers")
+	ctx := context.Background()
+
+	require.NoError(t, q.Publish(ctx, "orders.created", []byte("1")))
+	var first collector
+	stop := consume(t, q, "orders.created", &first)
+	assert.Eventually(t, func() bool { return len(first.received()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	stop()
+
+	// Messages published while no member is running wait for the group.
+	require.NoError(t, q.Publish(ctx, "orders.created", []byte("2")))
+	var second collector
+	consume(t, q, "orders.created", &second)
+	assert.Eventually(t, func() bool { return len(second.received()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"2"}, second.received())
+}
+
+func TestNATS_NackRedelivers(t *testing.T) {
+	js := newJetStream(t)
+	q := queue.NewNATS(js, "workers")
+
+	require.NoError(t, q.Publish(context.Background(), "orders.created", []byte("flaky")))
+	c := collector{failOnce: map[string]bool{"flaky": true}}
+	consume(t, q, "orders.created", &c)
+
+	assert.Eventually(t, func() bool { return len(c.received()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"flaky", "flaky"}, c.received())
+}
+
+func TestNATS_NoGroupReceivesNewMessages(t *testing.T) {
+	js := newJetStream(t)
+	q := queue.NewNATS(js, "")
+	ctx := context.Background()
+
+	require.NoError(t, q.Publish(ctx, "orders.created", []byte("before")))
+	var c collector
+	consume(t, q, "orders.created", &c)
+
+	// Consume subscribes in the background; publish until it is listening.
+	assert.Eventually(t, func() bool {
+		assert.NoError(t, q.Publish(ctx, "orders.created", []byte("after")))
+		return len(c.received()) > 0
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.NotContains(t, c.received(), "before")
+}
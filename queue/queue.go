@@ -0,0 +1,128 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	awsclient "github.com/bpurdy1/golang-packages/aws-client"
+	"github.com/bpurdy1/golang-packages/envparse"
+	natsclient "github.com/bpurdy1/golang-packages/nats-client"
+)
+
+const (
+	BackendSQS  = "sqs"
+	BackendNATS = "nats"
+)
+
+// Config selects the message broker backing a Queue.
+type Config struct {
+	Backend   string `env:"QUEUE_BACKEND" envDefault:"sqs"`
+	NATSGroup string `env:"QUEUE_NATS_GROUP"`
+}
+
+// NewConfig parses environment variables into the Config struct
+func NewConfig() (*Config, error) {
+	cfg := &Config{}
+	if err := envparse.Parse(cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse queue config: %w", err)
+	}
+	return cfg, nil
+}
+
+// Publisher sends a payload to a topic. For SQS the topic is the queue URL,
+// for NATS it is the subject.
+type Publisher interface {
+	Publish(ctx context.Context, topic string, data []byte) error
+}
+
+// Consumer delivers messages from a topic to a handler until ctx is cancelled.
+type Consumer interface {
+	Consume(ctx context.Context, topic string, handler Handler) error
+}
+
+// Queue is a transport-agnostic message broker.
+type Queue interface {
+	Publisher
+	Consumer
+}
+
+// Handler processes a message. Messages are acked when the handler returns nil
+// and nacked when it returns an error, unless the handler already called Ack
+// or Nack itself.
+type Handler func(ctx context.Context, msg *Message) error
+
+// Message is a message received from a Queue.
+type Message struct {
+	ID    string
+	Topic string
+	Data  []byte
+
+	once sync.Once
+	ack  func(ctx context.Context) error
+	nack func(ctx context.Context) error
+}
+
+// Ack marks the message as processed. Only the first Ack or Nack takes effect.
+func (m *Message) Ack(ctx context.Context) error {
+	var err error
+	m.once.Do(func() {
+		if m.ack != nil {
+			err = m.ack(ctx)
+		}
+	})
+	return err
+}
+
+// Nack marks the message as failed so the broker may redeliver it. Only the
+// first Ack or Nack takes effect.
+func (m *Message) Nack(ctx context.Context) error {
+	var err error
+	m.once.Do(func() {
+		if m.nack != nil {
+			err = m.nack(ctx)
+		}
+	})
+	return err
+}
+
+func handle(ctx context.Context, handler Handler, msg *Message) error {
+	if err := handler(ctx, msg); err != nil {
+		return msg.Nack(ctx)
+	}
+	return msg.Ack(ctx)
+}
+
+// New connects to the backend selected by cfg, loading the broker's own
+// configuration from the environment.
+func New(ctx context.Context, cfg *Config) (Queue, error) {
+	switch cfg.Backend {
+	case BackendSQS:
+		awsCfg, err := awsclient.LoadConfig()
+		if err != nil {
+			return nil, err
+		}
+		client, err := awsclient.New(ctx, awsCfg)
+		if err != nil {
+			return nil, err
+		}
+		return NewSQS(client), nil
+	case BackendNATS:
+		natsCfg, err := natsclient.NewConfig()
+		if err != nil {
+			return nil, err
+		}
+		client, err := natsclient.NewClient(natsCfg)
+		if err != nil {
+			return nil, err
+		}
+		js, err := client.(*natsclient.NatsClient).JetStream()
+		if err != nil {
+			client.Close()
+			return nil, err
+		}
+		return NewNATS(js, cfg.NATSGroup), nil
+	default:
+		return nil, fmt.Errorf("queue: unknown backend %q", cfg.Backend)
+	}
+}
@@ -0,0 +1,99 @@
+package queue
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	awsclient "github.com/bpurdy1/golang-packages/aws-client"
+)
+
+// SQS is a Queue backed by Amazon SQS. Topics are queue URLs.
+type SQS struct {
+	client       awsclient.Client
+	batchSize    int32
+	pollInterval time.Duration
+	logger       *slog.Logger
+}
+
+type SQSOption func(*SQS)
+
+// WithBatchSize sets the maximum number of messages fetched per receive call (default: 10).
+func WithBatchSize(n int32) SQSOption {
+	return func(q *SQS) {
+		q.batchSize = n
+	}
+}
+
+// WithPollInterval sets how long Consume waits after an empty receive (default: 1s).
+func WithPollInterval(d time.Duration) SQSOption {
+	return func(q *SQS) {
+		q.pollInterval = d
+	}
+}
+
+// WithLogger sets the logger used for messages that could not be deleted
+// (default: slog.Default()).
+func WithLogger(logger *slog.Logger) SQSOption {
+	return func(q *SQS) {
+		q.logger = logger
+	}
+}
+
+// NewSQS creates a Queue on top of an aws-client Client.
+func NewSQS(client awsclient.Client, opts ...SQSOption) *SQS {
+	q := &SQS{
+		client:       client,
+		batchSize:    10,
+		pollInterval: time.Second,
+		logger:       slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// Publish sends data as the message body to the queue at topic.
+func (q *SQS) Publish(ctx context.Context, topic string, data []byte) error {
+	_, err := q.client.SendMessage(ctx, topic, string(data))
+	return err
+}
+
+// Consume polls the queue at topic and runs handler for each message until ctx
+// is cancelled. Acked messages are deleted; nacked messages are left in place
+// and become visible again once their visibility timeout expires. A message
+// that fails to delete is logged and redelivered like a nacked one.
+func (q *SQS) Consume(ctx context.Context, topic string, handler Handler) error {
+	for {
+		msgs, err := q.client.ReceiveMessages(ctx, topic, q.batchSize)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		for _, m := range msgs {
+			msg := &Message{
+				ID:    m.ID,
+				Topic: topic,
+				Data:  []byte(m.Body),
+				ack: func(ctx context.Context) error {
+					return q.client.DeleteMessage(ctx, topic, m.ReceiptHandle)
+				},
+			}
+			if err := handle(ctx, handler, msg); err != nil {
+				q.logger.Error("queue: failed to delete message", "queue", topic, "message_id", m.ID, "error", err)
+			}
+		}
+
+		if len(msgs) == 0 {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(q.pollInterval):
+			}
+		}
+	}
+}
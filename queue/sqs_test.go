@@ -0,0 +1,148 @@
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	awsclient "github.com/bpurdy1/golang-packages/aws-client"
+	"github.com/bpurdy1/golang-packages/aws-client/mock"
+	"github.com/bpurdy1/golang-packages/queue"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const queueURL = "https://sqs.us-east-1.amazonaws.com/123456789/test-queue"
+
+func TestSQS_Publish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := mock.NewMockClient(ctrl)
+	ctx := context.Background()
+
+	mockClient.EXPECT().
+		SendMessage(ctx, queueURL, "hello").
+		Return("msg-1", nil)
+
+	q := queue.NewSQS(mockClient)
+	assert.NoError(t, q.Publish(ctx, queueURL, []byte("hello")))
+}
+
+func TestSQS_Consume_AcksAndNacks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := mock.NewMockClient(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockClient.EXPECT().
+		ReceiveMessages(gomock.Any(), queueURL, int32(10)).
+		Return([]awsclient.Message{
+			{ID: "msg-1", Body: "ok", ReceiptHandle: "handle-1"},
+			{ID: "msg-2", Body: "fail", ReceiptHandle: "handle-2"},
+		}, nil)
+	mockClient.EXPECT().
+		ReceiveMessages(gomock.Any(), queueURL, int32(10)).
+		DoAndReturn(func(context.Context, string, int32) ([]awsclient.Message, error) {
+			cancel()
+			return nil, nil
+		})
+	// Only the successfully handled message is deleted.
+	mockClient.EXPECT().
+		DeleteMessage(gomock.Any(), queueURL, "handle-1").
+		Return(nil)
+
+	var seen []string
+	q := queue.NewSQS(mockClient, queue.WithPollInterval(time.Millisecond))
+	err := q.Consume(ctx, queueURL, func(_ context.Context, msg *queue.Message) error {
+		seen = append(seen, string(msg.Data))
+		if string(msg.Data) == "fail" {
+			return errors.New("handler failed")
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ok", "fail"}, seen)
+}
+
+func TestSQS_Consume_ReceiveError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := mock.NewMockClient(ctrl)
+	ctx := context.Background()
+
+	receiveErr := errors.New("access denied")
+	mockClient.EXPECT().
+		ReceiveMessages(ctx, queueURL, int32(5)).
+		Return(nil, receiveErr)
+
+	q := queue.NewSQS(mockClient, queue.WithBatchSize(5))
+	err := q.Consume(ctx, queueURL, func(context.Context, *queue.Message) error { return nil })
+	assert.ErrorIs(t, err, receiveErr)
+}
+
+func TestMessage_AckOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := mock.NewMockClient(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockClient.EXPECT().
+		ReceiveMessages(gomock.Any(), queueURL, int32(10)).
+		Return([]awsclient.Message{{ID: "msg-1", Body: "manual", ReceiptHandle: "handle-1"}}, nil)
+	mockClient.EXPECT().
+		ReceiveMessages(gomock.Any(), queueURL, int32(10)).
+		DoAndReturn(func(context.Context, string, int32) ([]awsclient.Message, error) {
+			cancel()
+			return nil, nil
+		})
+	mockClient.EXPECT().
+		DeleteMessage(gomock.Any(), queueURL, "handle-1").
+		Return(nil).
+		Times(1)
+
+	q := queue.NewSQS(mockClient, queue.WithPollInterval(time.Millisecond))
+	err := q.Consume(ctx, queueURL, func(ctx context.Context, msg *queue.Message) error {
+		return msg.Ack(ctx)
+	})
+	assert.NoError(t, err)
+}
+
+func TestSQS_Consume_DeleteErrorContinues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := mock.NewMockClient(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockClient.EXPECT().
+		ReceiveMessages(gomock.Any(), queueURL, int32(10)).
+		Return([]awsclient.Message{
+			{ID: "msg-1", Body: "first", ReceiptHandle: "handle-1"},
+			{ID: "msg-2", Body: "second", ReceiptHandle: "handle-2"},
+		}, nil)
+	mockClient.EXPECT().
+		ReceiveMessages(gomock.Any(), queueURL, int32(10)).
+		DoAndReturn(func(context.Context, string, int32) ([]awsclient.Message, error) {
+			cancel()
+			return nil, nil
+		})
+	mockClient.EXPECT().
+		DeleteMessage(gomock.Any(), queueURL, "handle-1").
+		Return(errors.New("receipt handle expired"))
+	mockClient.EXPECT().
+		DeleteMessage(gomock.Any(), queueURL, "handle-2").
+		Return(nil)
+
+	var seen []string
+	q := queue.NewSQS(mockClient,
+		queue.WithPollInterval(time.Millisecond),
+		queue.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	err := q.Consume(ctx, queueURL, func(_ context.Context, msg *queue.Message) error {
+		seen = append(seen, string(msg.Data))
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, seen)
+}
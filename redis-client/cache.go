@@ -0,0 +1,297 @@
+package redisclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrPubSubUnsupported is returned by Cache.Listen when the client cannot
+// subscribe to channels.
+var ErrPubSubUnsupported = errors.New("redisclient: client does not support pub/sub")
+
+// SetJSON stores v as JSON under key for ttl (zero means no expiry). Like
+// Cache.Set it publishes an invalidation, on the channel set with
+// WithInvalidationChannel, so Caches running Listen drop their local copy of
+// key. The key is used as is; WithCachePrefix is ignored.
+func SetJSON(ctx context.Context, client Client, key string, v any, ttl time.Duration, opts ...CacheOption) error {
+	return NewCache[any](client, append(opts, WithCachePrefix(""))...).Set(ctx, key, v, ttl)
+}
+
+// GetJSON loads the JSON value stored under key. It returns redis.Nil when the
+// key does not exist.
+func GetJSON[T any](ctx context.Context, client Client, key string) (T, error) {
+	var v T
+	b, err := client.Get(ctx, key).Bytes()
+	if err != nil {
+		return v, err
+	}
+	err = json.Unmarshal(b, &v)
+	return v, err
+}
+
+type subscriber interface {
+	Subscribe(ctx context.Context, channels ...string) *redis.PubSub
+}
+
+// Cache layers reported to CacheMetrics.Hit.
+const (
+	CacheLayerLocal = "local"
+	CacheLayerRedis = "redis"
+)
+
+// CacheMetrics receives Cache events for monitoring hit ratios and load
+// latency. cache is the name set with WithCacheName. Implementations must be
+// safe for concurrent use; see the promcache package for Prometheus.
+type CacheMetrics interface {
+	// Hit records a lookup served by layer (CacheLayerLocal or CacheLayerRedis).
+	Hit(cache, layer string)
+	Miss(cache string)
+	// Load records a call to the loader passed to GetOrLoad.
+	Load(cache string, d time.Duration)
+	// Error records a failed operation: "get", "set", "delete", "load",
+	// "decode", "publish" or "listen".
+	Error(cache, op string)
+}
+
+type noopCacheMetrics struct{}
+
+func (noopCacheMetrics) Hit(string, string)         {}
+func (noopCacheMetrics) Miss(string)                {}
+func (noopCacheMetrics) Load(string, time.Duration) {}
+func (noopCacheMetrics) Error(string, string)       {}
+
+type cacheOptions struct {
+	name     string
+	prefix   string
+	local    LocalCache
+	localTTL time.Duration
+	channel  string
+	metrics  CacheMetrics
+}
+
+type CacheOption func(*cacheOptions)
+
+// WithCachePrefix sets the prefix prepended to every key (default: "cache:").
+func WithCachePrefix(prefix string) CacheOption {
+	return func(o *cacheOptions) {
+		o.prefix = prefix
+	}
+}
+
+// WithCacheName names the cache in metrics (default: the key prefix).
+func WithCacheName(name string) CacheOption {
+	return func(o *cacheOptions) {
+		o.name = name
+	}
+}
+
+// WithCacheMetrics reports hits, misses, load durations and errors to m.
+func WithCacheMetrics(m CacheMetrics) CacheOption {
+	return func(o *cacheOptions) {
+		o.metrics = m
+	}
+}
+
+// WithLocalCache adds an in-process layer in front of Redis. Entries are kept
+// for at most ttl, and are evicted early when another instance writes the key
+// while Listen is running.
+func WithLocalCache(local LocalCache, ttl time.Duration) CacheOption {
+	return func(o *cacheOptions) {
+		o.local = local
+		o.localTTL = ttl
+	}
+}
+
+// WithInvalidationChannel sets the pub/sub channel used to broadcast writes
+// (default: "cache:invalidate"). Caches sharing a channel evict each other's
+// local entries.
+func WithInvalidationChannel(channel string) CacheOption {
+	return func(o *cacheOptions) {
+		o.channel = channel
+	}
+}
+
+// Cache is a typed, JSON-encoded cache-aside helper with an optional two-tier
+// layout: an in-process LocalCache in front of Redis. Every write publishes
+// an invalidation message so other instances running Listen drop their local
+// copy of the key.
+//
+//	users := redisclient.NewCache[User](client,
+//		redisclient.WithCachePrefix("user:"),
+//		redisclient.WithLocalCache(redisclient.NewLRU(10_000), time.Minute))
+//	go users.Listen(ctx)
+//	u, err := users.GetOrLoad(ctx, id, time.Hour, func(ctx context.Context) (User, error) {
+//		return db.GetUser(ctx, id)
+//	})
+type Cache[T any] struct {
+	client Client
+	opts   cacheOptions
+	// origin identifies this instance in invalidation messages so it ignores
+	// its own writes.
+	origin string
+}
+
+// NewCache creates a Cache storing values in client.
+func NewCache[T any](client Client, opts ...CacheOption) *Cache[T] {
+	c := &Cache[T]{
+		client: client,
+		opts: cacheOptions{
+			prefix:  "cache:",
+			channel: "cache:invalidate",
+			metrics: noopCacheMetrics{},
+		},
+	}
+	for _, opt := range opts {
+		opt(&c.opts)
+	}
+	if c.opts.name == "" {
+		c.opts.name = c.opts.prefix
+	}
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	c.origin = hex.EncodeToString(b)
+	return c
+}
+
+// Get returns the value for key and whether it was found.
+func (c *Cache[T]) Get(ctx context.Context, key string) (T, bool, error) {
+	var v T
+	b, ok, err := c.get(ctx, c.opts.prefix+key)
+	if err != nil || !ok {
+		return v, false, err
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return v, false, c.fail("decode", err)
+	}
+	return v, true, nil
+}
+
+func (c *Cache[T]) get(ctx context.Context, key string) ([]byte, bool, error) {
+	if c.opts.local != nil {
+		if b, ok := c.opts.local.Get(key); ok {
+			c.opts.metrics.Hit(c.opts.name, CacheLayerLocal)
+			return b, true, nil
+		}
+	}
+	b, err := c.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		c.opts.metrics.Miss(c.opts.name)
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, c.fail("get", err)
+	}
+	c.opts.metrics.Hit(c.opts.name, CacheLayerRedis)
+	if c.opts.local != nil {
+		c.opts.local.Set(key, b, c.opts.localTTL)
+	}
+	return b, true, nil
+}
+
+// Set stores value under key for ttl and invalidates other instances' local
+// copies.
+func (c *Cache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	key = c.opts.prefix + key
+	if err := c.client.Set(ctx, key, b, ttl).Err(); err != nil {
+		return c.fail("set", err)
+	}
+	if c.opts.local != nil {
+		c.opts.local.Set(key, b, c.opts.localTTL)
+	}
+	return c.publish(ctx, key)
+}
+
+// Delete removes key and invalidates other instances' local copies.
+func (c *Cache[T]) Delete(ctx context.Context, key string) error {
+	key = c.opts.prefix + key
+	if c.opts.local != nil {
+		c.opts.local.Delete(key)
+	}
+	if err := c.client.Del(ctx, key).Err(); err != nil {
+		return c.fail("delete", err)
+	}
+	return c.publish(ctx, key)
+}
+
+// GetOrLoad returns the cached value for key, or calls load and caches its
+// result for ttl on a miss. Errors from load are returned and not cached.
+func (c *Cache[T]) GetOrLoad(ctx context.Context, key string, ttl time.Duration, load func(context.Context) (T, error)) (T, error) {
+	v, ok, err := c.Get(ctx, key)
+	if err != nil || ok {
+		return v, err
+	}
+	start := time.Now()
+	v, err = load(ctx)
+	c.opts.metrics.Load(c.opts.name, time.Since(start))
+	if err != nil {
+		return v, c.fail("load", err)
+	}
+	return v, c.Set(ctx, key, v, ttl)
+}
+
+func (c *Cache[T]) publish(ctx context.Context, key string) error {
+	return c.fail("publish", c.client.Publish(ctx, c.opts.channel, c.origin+" "+key).Err())
+}
+
+// fail records err, if any, against op and returns it.
+func (c *Cache[T]) fail(op string, err error) error {
+	if err != nil {
+		c.opts.metrics.Error(c.opts.name, op)
+	}
+	return err
+}
+
+// Listen subscribes to the invalidation channel and evicts local entries
+// written by other instances until ctx is cancelled. The local cache is
+// cleared whenever the subscription is (re)established, since invalidations
+// sent while disconnected are lost. It is a no-op without a local cache.
+func (c *Cache[T]) Listen(ctx context.Context) error {
+	if c.opts.local == nil {
+		return nil
+	}
+	sub, ok := c.client.(subscriber)
+	if !ok {
+		return ErrPubSubUnsupported
+	}
+
+	pubsub := sub.Subscribe(ctx, c.opts.channel)
+	defer pubsub.Close()
+
+	for {
+		msg, err := pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			c.fail("listen", err)
+			// go-redis reconnects on the next Receive; avoid spinning meanwhile.
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		switch msg := msg.(type) {
+		case *redis.Subscription:
+			c.opts.local.Clear()
+		case *redis.Message:
+			origin, key, _ := strings.Cut(msg.Payload, " ")
+			if origin != c.origin {
+				c.opts.local.Delete(key)
+			}
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package redisclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestClient(t *testing.T) (Client, *miniredis.Miniredis) {
+	t.Helper()
+	m := miniredis.RunT(t)
+	client := NewClientOptions(func(o *redis.Options) { o.Addr = m.Addr() })
+	t.Cleanup(func() { client.Close() })
+	return client, m
+}
+
+// eventually polls cond for up to a second, since invalidations are
+// delivered asynchronously.
+func eventually(t *testing.T, cond func() bool, msg string) {
+	t.Helper()
+	for range 100 {
+		if cond() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal(msg)
+}
+
+type countingMetrics struct {
+	mu     sync.Mutex
+	hits   map[string]int
+	misses int
+	loads  int
+	errors map[string]int
+}
+
+func newCountingMetrics() *countingMetrics {
+	return &countingMetrics{hits: map[string]int{}, errors: map[string]int{}}
+}
+
+func (m *countingMetrics) Hit(_, layer string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hits[layer]++
+}
+
+func (m *countingMetrics) Miss(string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.misses++
+}
+
+func (m *countingMetrics) Load(string, time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.loads++
+}
+
+func (m *countingMetrics) Error(_, op string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errors[op]++
+}
+
+type user struct {
+	Name string
+}
+
+// listening starts Listen on c and waits until it has subscribed, which it
+// signals by clearing the local cache.
+func listening(t *testing.T, ctx context.Context, c *Cache[user]) {
+	t.Helper()
+	c.opts.local.Set("listening", nil, 0)
+	go c.Listen(ctx)
+	eventually(t, func() bool {
+		_, ok := c.opts.local.Get("listening")
+		return !ok
+	}, "Listen did not subscribe")
+}
+
+func TestCache_GetOrLoad(t *testing.T) {
+	client, m := newTestClient(t)
+	ctx := context.Background()
+	metrics := newCountingMetrics()
+	c := NewCache[user](client, WithCachePrefix("user:"), WithCacheMetrics(metrics))
+
+	loads := 0
+	load := func(context.Context) (user, error) {
+		loads++
+		return user{Name: "alice"}, nil
+	}
+	for range 2 {
+		u, err := c.GetOrLoad(ctx, "1", time.Hour, load)
+		if err != nil || u.Name != "alice" {
+			t.Fatalf("GetOrLoad = %v, %v", u, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("loader called %d times, want 1", loads)
+	}
+	if !m.Exists("user:1") {
+		t.Error("value not stored under the prefixed key")
+	}
+	if m.TTL("user:1") != time.Hour {
+		t.Errorf("ttl = %v, want 1h", m.TTL("user:1"))
+	}
+	if metrics.misses != 1 || metrics.hits[CacheLayerRedis] != 1 || metrics.loads != 1 {
+		t.Errorf("metrics = %+v", metrics)
+	}
+
+	loadErr := errors.New("db down")
+	if _, err := c.GetOrLoad(ctx, "2", time.Hour, func(context.Context) (user, error) {
+		return user{}, loadErr
+	}); !errors.Is(err, loadErr) {
+		t.Errorf("GetOrLoad error = %v, want %v", err, loadErr)
+	}
+	if m.Exists("user:2") {
+		t.Error("failed load was cached")
+	}
+	if metrics.errors["load"] != 1 {
+		t.Errorf("load errors = %d, want 1", metrics.errors["load"])
+	}
+}
+
+func TestCache_LocalLayer(t *testing.T) {
+	client, m := newTestClient(t)
+	ctx := context.Background()
+	metrics := newCountingMetrics()
+	local := NewLRU(10)
+	c := NewCache[user](client, WithLocalCache(local, time.Minute), WithCacheMetrics(metrics))
+
+	if err := c.Set(ctx, "1", user{Name: "alice"}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	// Served from the local layer even once Redis no longer has the key.
+	m.Del("cache:1")
+	u, ok, err := c.Get(ctx, "1")
+	if err != nil || !ok || u.Name != "alice" {
+		t.Fatalf("Get = %v, %v, %v", u, ok, err)
+	}
+	if metrics.hits[CacheLayerLocal] != 1 {
+		t.Errorf("local hits = %d, want 1", metrics.hits[CacheLayerLocal])
+	}
+
+	if err := c.Delete(ctx, "1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := c.Get(ctx, "1"); ok {
+		t.Error("deleted key still cached")
+	}
+}
+
+func TestCache_Invalidation(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	localA, localB := NewLRU(10), NewLRU(10)
+	a := NewCache[user](client, WithLocalCache(localA, time.Minute))
+	b := NewCache[user](client, WithLocalCache(localB, time.Minute))
+	listening(t, ctx, a)
+	listening(t, ctx, b)
+
+	if err := a.Set(ctx, "1", user{Name: "alice"}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := b.Get(ctx, "1"); !ok {
+		t.Fatal("b did not read the key")
+	}
+	if _, ok := localB.Get("cache:1"); !ok {
+		t.Fatal("b did not populate its local layer")
+	}
+
+	// A write by b evicts a's copy but keeps b's own.
+	if err := b.Set(ctx, "1", user{Name: "bob"}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, func() bool {
+		_, ok := localA.Get("cache:1")
+		return !ok
+	}, "a's local copy was not invalidated")
+	if _, ok := localB.Get("cache:1"); !ok {
+		t.Error("b evicted its own write")
+	}
+	if u, _, _ := a.Get(ctx, "1"); u.Name != "bob" {
+		t.Errorf("a read %q after invalidation, want bob", u.Name)
+	}
+
+	if err := a.Delete(ctx, "1"); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, func() bool {
+		_, ok := localB.Get("cache:1")
+		return !ok
+	}, "b's local copy was not invalidated by Delete")
+}
+
+func TestSetJSON_Invalidates(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	local := NewLRU(10)
+	c := NewCache[user](client, WithLocalCache(local, time.Minute))
+	listening(t, ctx, c)
+
+	if err := c.Set(ctx, "1", user{Name: "alice"}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetJSON(ctx, client, "cache:1", user{Name: "bob"}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, func() bool {
+		_, ok := local.Get("cache:1")
+		return !ok
+	}, "SetJSON did not invalidate the local copy")
+
+	u, err := GetJSON[user](ctx, client, "cache:1")
+	if err != nil || u.Name != "bob" {
+		t.Errorf("GetJSON = %v, %v; want bob", u, err)
+	}
+	if _, err := GetJSON[user](ctx, client, "missing"); !errors.Is(err, redis.Nil) {
+		t.Errorf("GetJSON missing key error = %v, want redis.Nil", err)
+	}
+}
+
+func TestCache_ListenWithoutLocal(t *testing.T) {
+	client, _ := newTestClient(t)
+	if err := NewCache[user](client).Listen(context.Background()); err != nil {
+		t.Errorf("Listen without a local cache = %v, want nil", err)
+	}
+}
@@ -0,0 +1,180 @@
+package redisclient
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrInvalidRenewInterval is returned by Elect when the renew interval is not
+// shorter than the lock ttl.
+var ErrInvalidRenewInterval = errors.New("redisclient: renew interval must be shorter than the ttl")
+
+// Leadership is a handle on a running leader election started by Elect.
+type Leadership struct {
+	lock     *Lock
+	ttl      time.Duration
+	interval time.Duration
+	onElect  func()
+	onLost   func()
+
+	// renewed is when the lock was last acquired or extended, as an offset
+	// from start so it uses the monotonic clock.
+	start   time.Time
+	renewed atomic.Int64
+	leader  atomic.Bool
+	done    chan struct{}
+}
+
+type ElectionOption func(*Leadership)
+
+// WithRenewInterval sets how often the leader renews its lock and followers
+// retry acquiring it (default: ttl/3). It must be shorter than the ttl.
+func WithRenewInterval(d time.Duration) ElectionOption {
+	return func(l *Leadership) {
+		l.interval = d
+	}
+}
+
+// OnElected sets a callback run each time this instance becomes leader.
+func OnElected(fn func()) ElectionOption {
+	return func(l *Leadership) {
+		l.onElect = fn
+	}
+}
+
+// OnLost sets a callback run each time this instance stops being leader,
+// either because renewal failed or because the election was stopped.
+func OnLost(fn func()) ElectionOption {
+	return func(l *Leadership) {
+		l.onLost = fn
+	}
+}
+
+// Elect campaigns for leadership of key until ctx is cancelled. The leader
+// holds a Lock with the given ttl and renews it periodically; followers keep
+// retrying so another replica takes over when the leader goes away. On
+// cancellation the lock is released so a successor is elected immediately.
+//
+// Leadership is given up once ttl has passed since the last successful
+// renewal, even if Redis has not answered yet, since by then another replica
+// may have taken the lock.
+//
+//	l, err := redisclient.Elect(ctx, client, "jobs:outbox-relay", 15*time.Second,
+//		redisclient.OnLost(func() { cancelRelay() }))
+//	if l.IsLeader() { ... }
+func Elect(ctx context.Context, client Client, key string, ttl time.Duration, opts ...ElectionOption) (*Leadership, error) {
+	l := &Leadership{
+		lock:     NewLock(client, key, ttl),
+		ttl:      ttl,
+		interval: ttl / 3,
+		start:    time.Now(),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if l.interval <= 0 || l.interval >= ttl {
+		return nil, ErrInvalidRenewInterval
+	}
+
+	go l.run(ctx)
+	return l, nil
+}
+
+// IsLeader reports whether this instance currently holds leadership.
+func (l *Leadership) IsLeader() bool {
+	return l.leader.Load() && !l.expired()
+}
+
+// Done is closed once the election has stopped and the lock has been released.
+func (l *Leadership) Done() <-chan struct{} {
+	return l.done
+}
+
+func (l *Leadership) run(ctx context.Context) {
+	defer close(l.done)
+
+	for {
+		l.campaign(ctx)
+
+		// Wake up when the lock would expire if that comes before the next
+		// renewal, so leadership is dropped on time.
+		wait := l.interval
+		if left := l.remaining(); l.leader.Load() && left < wait {
+			wait = left
+		}
+
+		select {
+		case <-ctx.Done():
+			if l.leader.Load() {
+				releaseCtx, cancel := context.WithTimeout(context.Background(), l.interval)
+				_ = l.lock.Release(releaseCtx)
+				cancel()
+				l.setLeader(false)
+			}
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (l *Leadership) campaign(ctx context.Context) {
+	if l.leader.Load() {
+		l.renew(ctx)
+		return
+	}
+
+	started := time.Since(l.start)
+	ok, err := l.lock.TryAcquire(ctx)
+	if err == nil && ok {
+		l.renewed.Store(int64(started))
+		l.setLeader(true)
+	}
+}
+
+// renew extends the lock, giving up by the time it would have expired. A
+// failed attempt keeps leadership as long as the lock is still valid, unless
+// Redis reports that someone else holds it.
+func (l *Leadership) renew(ctx context.Context) {
+	if l.expired() {
+		l.setLeader(false)
+		return
+	}
+
+	deadline := l.start.Add(time.Duration(l.renewed.Load()) + l.ttl)
+	extendCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	started := time.Since(l.start)
+	err := l.lock.Extend(extendCtx)
+	switch {
+	case err == nil:
+		l.renewed.Store(int64(started))
+	case errors.Is(err, ErrLockNotHeld), l.expired():
+		l.setLeader(false)
+	}
+}
+
+// remaining returns how long the lock stays valid after its last renewal.
+func (l *Leadership) remaining() time.Duration {
+	return l.ttl - (time.Since(l.start) - time.Duration(l.renewed.Load()))
+}
+
+// expired reports whether ttl has passed since the lock was last renewed.
+func (l *Leadership) expired() bool {
+	return l.remaining() <= 0
+}
+
+func (l *Leadership) setLeader(leader bool) {
+	if l.leader.Swap(leader) == leader {
+		return
+	}
+	if leader && l.onElect != nil {
+		l.onElect()
+	}
+	if !leader && l.onLost != nil {
+		l.onLost()
+	}
+}
@@ -0,0 +1,142 @@
+package redisclient
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const (
+	testElectionTTL      = 300 * time.Millisecond
+	testElectionInterval = 50 * time.Millisecond
+)
+
+type electionEvents struct {
+	elected atomic.Int32
+	lost    atomic.Int32
+}
+
+func (e *electionEvents) options() []ElectionOption {
+	return []ElectionOption{
+		WithRenewInterval(testElectionInterval),
+		OnElected(func() { e.elected.Add(1) }),
+		OnLost(func() { e.lost.Add(1) }),
+	}
+}
+
+func TestElect_InvalidRenewInterval(t *testing.T) {
+	client, _ := newTestClient(t)
+	for _, d := range []time.Duration{0, time.Second, 2 * time.Second} {
+		_, err := Elect(context.Background(), client, "leader", time.Second, WithRenewInterval(d))
+		if !errors.Is(err, ErrInvalidRenewInterval) {
+			t.Errorf("WithRenewInterval(%v): err = %v, want ErrInvalidRenewInterval", d, err)
+		}
+	}
+}
+
+func TestElect_SingleLeader(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var ea, eb electionEvents
+	a, err := Elect(ctx, client, "leader", testElectionTTL, ea.options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Elect(ctx, client, "leader", testElectionTTL, eb.options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eventually(t, func() bool { return a.IsLeader() || b.IsLeader() }, "no leader elected")
+	// Several renewals later there is still exactly one leader.
+	time.Sleep(4 * testElectionInterval)
+	if a.IsLeader() == b.IsLeader() {
+		t.Fatalf("a.IsLeader() = %v, b.IsLeader() = %v, want exactly one", a.IsLeader(), b.IsLeader())
+	}
+	if n := ea.elected.Load() + eb.elected.Load(); n != 1 {
+		t.Errorf("OnElected ran %d times, want 1", n)
+	}
+
+	cancel()
+	<-a.Done()
+	<-b.Done()
+	if a.IsLeader() || b.IsLeader() {
+		t.Error("leadership held after the election stopped")
+	}
+	if n := ea.lost.Load() + eb.lost.Load(); n != 1 {
+		t.Errorf("OnLost ran %d times, want 1", n)
+	}
+}
+
+func TestElect_Handover(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctxA, cancelA := context.WithCancel(context.Background())
+	defer cancelA()
+	ctxB, cancelB := context.WithCancel(context.Background())
+	defer cancelB()
+
+	var ea, eb electionEvents
+	a, err := Elect(ctxA, client, "leader", testElectionTTL, ea.options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, a.IsLeader, "a not elected")
+	b, err := Elect(ctxB, client, "leader", testElectionTTL, eb.options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Stopping the leader releases the lock, so b takes over within one
+	// retry interval rather than waiting for the ttl.
+	cancelA()
+	<-a.Done()
+	if ea.lost.Load() != 1 {
+		t.Errorf("a: OnLost ran %d times, want 1", ea.lost.Load())
+	}
+	eventually(t, b.IsLeader, "b did not take over")
+}
+
+func TestElect_LostWhenLockTaken(t *testing.T) {
+	client, m := newTestClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var e electionEvents
+	l, err := Elect(ctx, client, "leader", testElectionTTL, e.options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, l.IsLeader, "not elected")
+
+	// Another owner overwrites the lock; the next renewal notices.
+	m.Set("leader", "someone-else")
+	eventually(t, func() bool { return e.lost.Load() == 1 }, "OnLost not called")
+	if l.IsLeader() {
+		t.Error("IsLeader() = true after losing the lock")
+	}
+}
+
+func TestElect_LostWhenRedisUnreachable(t *testing.T) {
+	client, m := newTestClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var e electionEvents
+	l, err := Elect(ctx, client, "leader", testElectionTTL, e.options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, l.IsLeader, "not elected")
+
+	// Without an answer from Redis leadership is kept until the ttl runs
+	// out, then given up.
+	m.Close()
+	eventually(t, func() bool { return e.lost.Load() == 1 }, "OnLost not called")
+	if l.IsLeader() {
+		t.Error("IsLeader() = true after the ttl passed")
+	}
+}
@@ -3,12 +3,17 @@ module github.com/bpurdy1/golang-packages/redis-client
 go 1.25.6
 
 require (
-	github.com/caarlos0/env/v11 v11.3.1
+	github.com/alicebob/miniredis/v2 v2.39.0
+	github.com/bpurdy1/golang-packages/envparse v0.0.0-00010101000000-000000000000
 	github.com/redis/go-redis/v9 v9.17.2
 	go.uber.org/mock v0.6.0
 )
 
 require (
+	github.com/caarlos0/env/v11 v11.3.1 // indirect
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
 )
+
+replace github.com/bpurdy1/golang-packages/envparse => ../envparse
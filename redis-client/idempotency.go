@@ -0,0 +1,196 @@
+package redisclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrIdempotencyKeyNotFound is returned by Complete when the key was never
+	// started or has already expired.
+	ErrIdempotencyKeyNotFound = errors.New("redisclient: idempotency key not found")
+	// ErrIdempotencyNotOwner is returned by Complete when the key is not in
+	// progress under the caller's token, e.g. because it expired and was
+	// claimed by another caller, or was already completed.
+	ErrIdempotencyNotOwner = errors.New("redisclient: idempotency key not owned by caller")
+	// ErrIdempotencyTTL is returned by Begin for a ttl below one millisecond,
+	// which Redis would treat as already expired.
+	ErrIdempotencyTTL = errors.New("redisclient: idempotency ttl must be at least 1ms")
+)
+
+// IdempotencyState describes what a caller should do with a request after Begin.
+type IdempotencyState int
+
+const (
+	// IdempotencyStarted means the caller owns the key and should process the request.
+	IdempotencyStarted IdempotencyState = iota
+	// IdempotencyDuplicate means another caller is still processing the same key.
+	IdempotencyDuplicate
+	// IdempotencyCompleted means the request was already processed; the stored
+	// result is returned alongside.
+	IdempotencyCompleted
+)
+
+func (s IdempotencyState) String() string {
+	switch s {
+	case IdempotencyStarted:
+		return "started"
+	case IdempotencyDuplicate:
+		return "duplicate"
+	case IdempotencyCompleted:
+		return "completed"
+	default:
+		return fmt.Sprintf("IdempotencyState(%d)", int(s))
+	}
+}
+
+const (
+	idemStateStarted   = "started"
+	idemStateCompleted = "completed"
+)
+
+// beginScript claims the key for the owner token if it is free, otherwise
+// returns its state and result.
+var beginScript = redis.NewScript(`
+if redis.call('HSETNX', KEYS[1], 'state', ARGV[1]) == 1 then
+	redis.call('HSET', KEYS[1], 'owner', ARGV[3])
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return {ARGV[1]}
+end
+return redis.call('HMGET', KEYS[1], 'state', 'result')
+`)
+
+// completeScript stores the result on a key still in progress under the owner
+// token, keeping its TTL.
+var completeScript = redis.NewScript(`
+local fields = redis.call('HMGET', KEYS[1], 'state', 'owner')
+if not fields[1] then
+	return 0
+end
+if fields[1] ~= ARGV[1] or fields[2] ~= ARGV[2] then
+	return -1
+end
+redis.call('HSET', KEYS[1], 'state', ARGV[3], 'result', ARGV[4])
+return 1
+`)
+
+// releaseScript deletes the key only while it is still in progress under the
+// owner token.
+var releaseScript = redis.NewScript(`
+local fields = redis.call('HMGET', KEYS[1], 'state', 'owner')
+if fields[1] == ARGV[1] and fields[2] == ARGV[2] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// Idempotency dedupes retried requests across processes using a Redis hash per
+// key holding its processing state and result.
+type Idempotency struct {
+	client Client
+	prefix string
+}
+
+type IdempotencyOption func(*Idempotency)
+
+// WithIdempotencyPrefix sets the prefix prepended to every key (default: "idem:").
+func WithIdempotencyPrefix(prefix string) IdempotencyOption {
+	return func(i *Idempotency) {
+		i.prefix = prefix
+	}
+}
+
+// NewIdempotency creates an Idempotency helper using the given client.
+func NewIdempotency(client Client, opts ...IdempotencyOption) *Idempotency {
+	i := &Idempotency{
+		client: client,
+		prefix: "idem:",
+	}
+	for _, opt := range opts {
+		opt(i)
+	}
+	return i
+}
+
+// Begin claims key for ttl, which must be at least a millisecond. It returns
+// IdempotencyStarted if the caller should process the request,
+// IdempotencyDuplicate if another caller holds the key, or
+// IdempotencyCompleted with the stored result if it was already processed.
+//
+// With IdempotencyStarted it also returns a token identifying the caller as
+// the key's owner, to be passed to Complete or Release.
+//
+//	state, result, token, err := idem.Begin(ctx, requestID, time.Hour)
+//	if state == redisclient.IdempotencyStarted {
+//		result, err = process(ctx)
+//		if err != nil {
+//			idem.Release(ctx, requestID, token)
+//			return err
+//		}
+//		err = idem.Complete(ctx, requestID, token, result)
+//	}
+func (i *Idempotency) Begin(ctx context.Context, key string, ttl time.Duration) (IdempotencyState, []byte, string, error) {
+	if ttl < time.Millisecond {
+		return 0, nil, "", ErrIdempotencyTTL
+	}
+
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	token := hex.EncodeToString(b)
+
+	vals, err := beginScript.Run(ctx, i.client, []string{i.prefix + key},
+		idemStateStarted, ttl.Milliseconds(), token).Slice()
+	if err != nil {
+		return 0, nil, "", err
+	}
+
+	state, _ := vals[0].(string)
+	switch state {
+	case idemStateStarted:
+		if len(vals) == 1 {
+			return IdempotencyStarted, nil, token, nil
+		}
+		return IdempotencyDuplicate, nil, "", nil
+	case idemStateCompleted:
+		var result []byte
+		if len(vals) > 1 {
+			if s, ok := vals[1].(string); ok {
+				result = []byte(s)
+			}
+		}
+		return IdempotencyCompleted, result, "", nil
+	default:
+		return 0, nil, "", fmt.Errorf("redisclient: unexpected idempotency state %q", state)
+	}
+}
+
+// Complete records result for key so later Begin calls return it. The key
+// keeps the TTL set by Begin. It returns ErrIdempotencyNotOwner unless the key
+// is still in progress under token.
+func (i *Idempotency) Complete(ctx context.Context, key, token string, result []byte) error {
+	ok, err := completeScript.Run(ctx, i.client, []string{i.prefix + key},
+		idemStateStarted, token, idemStateCompleted, result).Int()
+	if err != nil {
+		return err
+	}
+	switch ok {
+	case 0:
+		return ErrIdempotencyKeyNotFound
+	case -1:
+		return ErrIdempotencyNotOwner
+	}
+	return nil
+}
+
+// Release frees a key that is still in progress under token so the request can
+// be retried, typically after processing failed. Completed keys and keys
+// claimed by another caller are left untouched.
+func (i *Idempotency) Release(ctx context.Context, key, token string) error {
+	return releaseScript.Run(ctx, i.client, []string{i.prefix + key}, idemStateStarted, token).Err()
+}
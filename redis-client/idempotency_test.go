@@ -0,0 +1,132 @@
+package redisclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIdempotency_Lifecycle(t *testing.T) {
+	client, m := newTestClient(t)
+	ctx := context.Background()
+	idem := NewIdempotency(client)
+
+	state, _, token, err := idem.Begin(ctx, "req-1", time.Minute)
+	if err != nil || state != IdempotencyStarted || token == "" {
+		t.Fatalf("Begin = %v, %q, %v; want started with a token", state, token, err)
+	}
+	if m.TTL("idem:req-1") != time.Minute {
+		t.Errorf("ttl = %v, want 1m", m.TTL("idem:req-1"))
+	}
+
+	state, _, other, err := idem.Begin(ctx, "req-1", time.Minute)
+	if err != nil || state != IdempotencyDuplicate || other != "" {
+		t.Fatalf("second Begin = %v, %q, %v; want duplicate without a token", state, other, err)
+	}
+
+	if err := idem.Complete(ctx, "req-1", token, []byte("ok")); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	state, result, _, err := idem.Begin(ctx, "req-1", time.Minute)
+	if err != nil || state != IdempotencyCompleted || string(result) != "ok" {
+		t.Fatalf("Begin after Complete = %v, %q, %v; want completed with ok", state, result, err)
+	}
+	if ttl := m.TTL("idem:req-1"); ttl <= 0 || ttl > time.Minute {
+		t.Errorf("ttl after Complete = %v, want the ttl set by Begin", ttl)
+	}
+}
+
+func TestIdempotency_CompleteRequiresOwner(t *testing.T) {
+	client, m := newTestClient(t)
+	ctx := context.Background()
+	idem := NewIdempotency(client)
+
+	if err := idem.Complete(ctx, "missing", "token", nil); !errors.Is(err, ErrIdempotencyKeyNotFound) {
+		t.Errorf("Complete missing key = %v, want ErrIdempotencyKeyNotFound", err)
+	}
+
+	_, _, stale, err := idem.Begin(ctx, "req-1", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The claim expires and another caller takes over the key.
+	m.FastForward(time.Minute)
+	_, _, token, err := idem.Begin(ctx, "req-1", time.Minute)
+	if err != nil || token == "" {
+		t.Fatalf("Begin after expiry = %q, %v", token, err)
+	}
+
+	if err := idem.Complete(ctx, "req-1", stale, []byte("stale")); !errors.Is(err, ErrIdempotencyNotOwner) {
+		t.Errorf("Complete with stale token = %v, want ErrIdempotencyNotOwner", err)
+	}
+	if err := idem.Complete(ctx, "req-1", token, []byte("ok")); err != nil {
+		t.Fatalf("Complete by owner: %v", err)
+	}
+	if err := idem.Complete(ctx, "req-1", token, []byte("again")); !errors.Is(err, ErrIdempotencyNotOwner) {
+		t.Errorf("second Complete = %v, want ErrIdempotencyNotOwner", err)
+	}
+	if _, result, _, _ := idem.Begin(ctx, "req-1", time.Minute); string(result) != "ok" {
+		t.Errorf("result = %q, want ok", result)
+	}
+}
+
+func TestIdempotency_Release(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+	idem := NewIdempotency(client, WithIdempotencyPrefix("test:"))
+
+	_, _, token, _ := idem.Begin(ctx, "req-1", time.Minute)
+	if err := idem.Release(ctx, "req-1", "not-the-owner"); err != nil {
+		t.Fatal(err)
+	}
+	if state, _, _, _ := idem.Begin(ctx, "req-1", time.Minute); state != IdempotencyDuplicate {
+		t.Fatalf("Release by another caller freed the key: state = %v", state)
+	}
+
+	if err := idem.Release(ctx, "req-1", token); err != nil {
+		t.Fatal(err)
+	}
+	state, _, token, _ := idem.Begin(ctx, "req-1", time.Minute)
+	if state != IdempotencyStarted {
+		t.Fatalf("Begin after Release = %v, want started", state)
+	}
+
+	if err := idem.Complete(ctx, "req-1", token, []byte("ok")); err != nil {
+		t.Fatal(err)
+	}
+	if err := idem.Release(ctx, "req-1", token); err != nil {
+		t.Fatal(err)
+	}
+	if state, _, _, _ := idem.Begin(ctx, "req-1", time.Minute); state != IdempotencyCompleted {
+		t.Errorf("Release removed a completed key: state = %v", state)
+	}
+}
+
+func TestIdempotency_RejectsShortTTL(t *testing.T) {
+	client, m := newTestClient(t)
+	idem := NewIdempotency(client)
+
+	for _, ttl := range []time.Duration{0, -time.Second, 500 * time.Microsecond} {
+		if _, _, _, err := idem.Begin(context.Background(), "req-1", ttl); !errors.Is(err, ErrIdempotencyTTL) {
+			t.Errorf("Begin(ttl=%v) = %v, want ErrIdempotencyTTL", ttl, err)
+		}
+	}
+	if m.Exists("idem:req-1") {
+		t.Error("rejected Begin created the key")
+	}
+}
+
+func TestIdempotencyState_String(t *testing.T) {
+	tests := map[IdempotencyState]string{
+		IdempotencyStarted:   "started",
+		IdempotencyDuplicate: "duplicate",
+		IdempotencyCompleted: "completed",
+		IdempotencyState(9):  "IdempotencyState(9)",
+	}
+	for state, want := range tests {
+		if got := state.String(); got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	}
+}
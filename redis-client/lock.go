@@ -0,0 +1,82 @@
+package redisclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrLockNotHeld is returned when extending or releasing a lock owned by someone else.
+var ErrLockNotHeld = errors.New("redisclient: lock not held")
+
+// extendScript refreshes the TTL only if the caller still owns the lock.
+var extendScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// unlockScript deletes the key only if the caller still owns the lock.
+var unlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// Lock is a distributed lock on a single Redis key. Each Lock carries a random
+// token so only its owner can extend or release it.
+type Lock struct {
+	client Client
+	key    string
+	token  string
+	ttl    time.Duration
+}
+
+// NewLock creates a Lock on key that expires after ttl unless extended.
+func NewLock(client Client, key string, ttl time.Duration) *Lock {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return &Lock{
+		client: client,
+		key:    key,
+		token:  hex.EncodeToString(b),
+		ttl:    ttl,
+	}
+}
+
+// TryAcquire takes the lock if it is free and reports whether it succeeded.
+func (l *Lock) TryAcquire(ctx context.Context) (bool, error) {
+	return l.client.SetNX(ctx, l.key, l.token, l.ttl).Result()
+}
+
+// Extend resets the lock TTL. It returns ErrLockNotHeld if the lock expired or
+// was taken by someone else.
+func (l *Lock) Extend(ctx context.Context) error {
+	ok, err := extendScript.Run(ctx, l.client, []string{l.key}, l.token, l.ttl.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	if ok == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// Release frees the lock. It returns ErrLockNotHeld if the lock is no longer
+// owned by the caller.
+func (l *Lock) Release(ctx context.Context) error {
+	ok, err := unlockScript.Run(ctx, l.client, []string{l.key}, l.token).Int()
+	if err != nil {
+		return err
+	}
+	if ok == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package redisclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLock_AlreadyHeld(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+	a := NewLock(client, "lock:job", time.Second)
+	b := NewLock(client, "lock:job", time.Second)
+
+	if ok, err := a.TryAcquire(ctx); err != nil || !ok {
+		t.Fatalf("a.TryAcquire = %v, %v, want true", ok, err)
+	}
+	if ok, err := b.TryAcquire(ctx); err != nil || ok {
+		t.Fatalf("b.TryAcquire = %v, %v, want false while a holds the lock", ok, err)
+	}
+
+	if err := a.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := b.TryAcquire(ctx); err != nil || !ok {
+		t.Errorf("b.TryAcquire after release = %v, %v, want true", ok, err)
+	}
+}
+
+func TestLock_WrongToken(t *testing.T) {
+	client, m := newTestClient(t)
+	ctx := context.Background()
+	a := NewLock(client, "lock:job", time.Second)
+	b := NewLock(client, "lock:job", time.Second)
+
+	if ok, err := a.TryAcquire(ctx); err != nil || !ok {
+		t.Fatalf("a.TryAcquire = %v, %v, want true", ok, err)
+	}
+	if err := b.Extend(ctx); !errors.Is(err, ErrLockNotHeld) {
+		t.Errorf("b.Extend = %v, want ErrLockNotHeld", err)
+	}
+	if err := b.Release(ctx); !errors.Is(err, ErrLockNotHeld) {
+		t.Errorf("b.Release = %v, want ErrLockNotHeld", err)
+	}
+	if v, _ := m.Get("lock:job"); v != a.token {
+		t.Errorf("lock value = %q, want a's token", v)
+	}
+}
+
+func TestLock_Extend(t *testing.T) {
+	client, m := newTestClient(t)
+	ctx := context.Background()
+	l := NewLock(client, "lock:job", time.Second)
+
+	if ok, err := l.TryAcquire(ctx); err != nil || !ok {
+		t.Fatalf("TryAcquire = %v, %v, want true", ok, err)
+	}
+	m.FastForward(800 * time.Millisecond)
+	if err := l.Extend(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := m.TTL("lock:job"); ttl != time.Second {
+		t.Errorf("TTL after Extend = %v, want 1s", ttl)
+	}
+}
+
+func TestLock_Expiry(t *testing.T) {
+	client, m := newTestClient(t)
+	ctx := context.Background()
+	a := NewLock(client, "lock:job", time.Second)
+	b := NewLock(client, "lock:job", time.Second)
+
+	if ok, err := a.TryAcquire(ctx); err != nil || !ok {
+		t.Fatalf("a.TryAcquire = %v, %v, want true", ok, err)
+	}
+	m.FastForward(time.Second)
+
+	if ok, err := b.TryAcquire(ctx); err != nil || !ok {
+		t.Fatalf("b.TryAcquire after expiry = %v, %v, want true", ok, err)
+	}
+	if err := a.Extend(ctx); !errors.Is(err, ErrLockNotHeld) {
+		t.Errorf("a.Extend after expiry = %v, want ErrLockNotHeld", err)
+	}
+	if err := a.Release(ctx); !errors.Is(err, ErrLockNotHeld) {
+		t.Errorf("a.Release after expiry = %v, want ErrLockNotHeld", err)
+	}
+}
@@ -0,0 +1,79 @@
+package redisclient
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	l := NewLRU(2)
+	l.Set("a", []byte("1"), 0)
+	l.Set("b", []byte("2"), 0)
+	l.Get("a")
+	l.Set("c", []byte("3"), 0)
+
+	if _, ok := l.Get("b"); ok {
+		t.Error("b should have been evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := l.Get(key); !ok {
+			t.Errorf("%s should still be cached", key)
+		}
+	}
+	if l.Len() != 2 {
+		t.Errorf("Len = %d, want 2", l.Len())
+	}
+}
+
+func TestLRU_SetReplaces(t *testing.T) {
+	l := NewLRU(2)
+	l.Set("a", []byte("1"), 0)
+	l.Set("b", []byte("2"), 0)
+	l.Set("a", []byte("3"), 0)
+	l.Set("c", []byte("4"), 0)
+
+	if v, ok := l.Get("a"); !ok || string(v) != "3" {
+		t.Errorf("a = %q, %v; want 3, true", v, ok)
+	}
+	if _, ok := l.Get("b"); ok {
+		t.Error("b should have been evicted")
+	}
+}
+
+func TestLRU_Expiry(t *testing.T) {
+	l := NewLRU(10)
+	l.Set("short", []byte("1"), time.Millisecond)
+	l.Set("forever", []byte("2"), 0)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := l.Get("short"); ok {
+		t.Error("short should have expired")
+	}
+	if _, ok := l.Get("forever"); !ok {
+		t.Error("forever should not expire")
+	}
+	if l.Len() != 1 {
+		t.Errorf("Len = %d, want 1 after the expired entry is read", l.Len())
+	}
+}
+
+func TestLRU_DeleteAndClear(t *testing.T) {
+	l := NewLRU(10)
+	l.Set("a", []byte("1"), 0)
+	l.Set("b", []byte("2"), 0)
+
+	l.Delete("a")
+	l.Delete("missing")
+	if _, ok := l.Get("a"); ok {
+		t.Error("a should have been deleted")
+	}
+
+	l.Clear()
+	if l.Len() != 0 {
+		t.Errorf("Len = %d after Clear, want 0", l.Len())
+	}
+	l.Set("c", []byte("3"), 0)
+	if _, ok := l.Get("c"); !ok {
+		t.Error("LRU unusable after Clear")
+	}
+}
@@ -0,0 +1,30 @@
+module github.com/bpurdy1/golang-packages/redis-client/promcache
+
+go 1.25.6
+
+require (
+	github.com/alicebob/miniredis/v2 v2.39.0
+	github.com/bpurdy1/golang-packages/redis-client v0.0.0-00010101000000-000000000000
+	github.com/prometheus/client_golang v1.24.1
+	github.com/redis/go-redis/v9 v9.17.2
+)
+
+require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/bpurdy1/golang-packages/envparse v0.0.0-00010101000000-000000000000 // indirect
+	github.com/caarlos0/env/v11 v11.3.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/kylelemons/godebug v1.1.0 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/prometheus/client_model v0.6.2 // indirect
+	github.com/prometheus/common v0.70.1 // indirect
+	github.com/prometheus/procfs v0.21.1 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
+	golang.org/x/sys v0.47.0 // indirect
+	google.golang.org/protobuf v1.36.11 // indirect
+)
+
+replace github.com/bpurdy1/golang-packages/envparse => ../../envparse
+
+replace github.com/bpurdy1/golang-packages/redis-client => ..
@@ -0,0 +1,102 @@
+// Package promcache exports redisclient.Cache metrics to Prometheus. It is a
+// separate module so redis-client itself does not depend on Prometheus.
+//
+//	metrics := promcache.New(prometheus.DefaultRegisterer)
+//	users := redisclient.NewCache[User](client,
+//		redisclient.WithCacheName("users"),
+//		redisclient.WithCacheMetrics(metrics))
+package promcache
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics implements redisclient.CacheMetrics with Prometheus collectors. One
+// Metrics can be shared by every Cache; series are labelled by cache name.
+//
+// It does not import redis-client: the interface only uses builtin types, so
+// Metrics satisfies it structurally. The tests check that it still does.
+type Metrics struct {
+	hits   *prometheus.CounterVec
+	misses *prometheus.CounterVec
+	loads  *prometheus.HistogramVec
+	errors *prometheus.CounterVec
+}
+
+type config struct {
+	namespace string
+	buckets   []float64
+}
+
+type Option func(*config)
+
+// WithNamespace sets the metric name prefix (default: "redis").
+func WithNamespace(namespace string) Option {
+	return func(c *config) {
+		c.namespace = namespace
+	}
+}
+
+// WithLoadBuckets sets the histogram buckets for load durations in seconds
+// (default: prometheus.DefBuckets).
+func WithLoadBuckets(buckets []float64) Option {
+	return func(c *config) {
+		c.buckets = buckets
+	}
+}
+
+// New creates Metrics and registers its collectors with reg. It panics if
+// they are already registered, like prometheus.MustRegister.
+func New(reg prometheus.Registerer, opts ...Option) *Metrics {
+	cfg := &config{
+		namespace: "redis",
+		buckets:   prometheus.DefBuckets,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	m := &Metrics{
+		hits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.namespace,
+			Name:      "cache_hits_total",
+			Help:      "Cache lookups served, by layer.",
+		}, []string{"cache", "layer"}),
+		misses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.namespace,
+			Name:      "cache_misses_total",
+			Help:      "Cache lookups that found no value.",
+		}, []string{"cache"}),
+		loads: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: cfg.namespace,
+			Name:      "cache_load_duration_seconds",
+			Help:      "Time spent loading values on a cache miss.",
+			Buckets:   cfg.buckets,
+		}, []string{"cache"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.namespace,
+			Name:      "cache_errors_total",
+			Help:      "Failed cache operations, by operation.",
+		}, []string{"cache", "op"}),
+	}
+	reg.MustRegister(m.hits, m.misses, m.loads, m.errors)
+	return m
+}
+
+func (m *Metrics) Hit(cache, layer string) {
+	m.hits.WithLabelValues(cache, layer).Inc()
+}
+
+func (m *Metrics) Miss(cache string) {
+	m.misses.WithLabelValues(cache).Inc()
+}
+
+func (m *Metrics) Load(cache string, d time.Duration) {
+	m.loads.WithLabelValues(cache).Observe(d.Seconds())
+}
+
+func (m *Metrics) Error(cache, op string) {
+	m.errors.WithLabelValues(cache, op).Inc()
+}
@@ -0,0 +1,84 @@
+package promcache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	redisclient "github.com/bpurdy1/golang-packages/redis-client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+)
+
+var _ redisclient.CacheMetrics = (*Metrics)(nil)
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := New(reg, WithNamespace("app"))
+
+	m.Hit("users", redisclient.CacheLayerLocal)
+	m.Hit("users", redisclient.CacheLayerRedis)
+	m.Hit("users", redisclient.CacheLayerRedis)
+	m.Miss("users")
+	m.Load("users", 20*time.Millisecond)
+	m.Error("users", "get")
+	m.Error("orders", "get")
+
+	tests := []struct {
+		name string
+		c    prometheus.Collector
+		want float64
+	}{
+		{"local hits", m.hits.WithLabelValues("users", redisclient.CacheLayerLocal), 1},
+		{"redis hits", m.hits.WithLabelValues("users", redisclient.CacheLayerRedis), 2},
+		{"misses", m.misses.WithLabelValues("users"), 1},
+		{"users errors", m.errors.WithLabelValues("users", "get"), 1},
+		{"orders errors", m.errors.WithLabelValues("orders", "get"), 1},
+	}
+	for _, tt := range tests {
+		if got := testutil.ToFloat64(tt.c); got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	n, err := testutil.GatherAndCount(reg, "app_cache_load_duration_seconds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("load duration series = %d, want 1", n)
+	}
+}
+
+func TestMetrics_Cache(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redisclient.NewClientOptions(func(o *redis.Options) { o.Addr = mr.Addr() })
+	t.Cleanup(func() { client.Close() })
+
+	reg := prometheus.NewRegistry()
+	m := New(reg)
+	cache := redisclient.NewCache[string](client,
+		redisclient.WithCacheName("users"),
+		redisclient.WithCacheMetrics(m))
+	ctx := context.Background()
+
+	load := func(context.Context) (string, error) { return "alice", nil }
+	if _, err := cache.GetOrLoad(ctx, "1", time.Minute, load); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.GetOrLoad(ctx, "1", time.Minute, load); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := testutil.ToFloat64(m.misses.WithLabelValues("users")); got != 1 {
+		t.Errorf("misses = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.hits.WithLabelValues("users", redisclient.CacheLayerRedis)); got != 1 {
+		t.Errorf("redis hits = %v, want 1", got)
+	}
+	if n := testutil.CollectAndCount(m.loads, "redis_cache_load_duration_seconds"); n != 1 {
+		t.Errorf("load duration series = %d, want 1", n)
+	}
+}
@@ -3,22 +3,27 @@ package redisclient
 
 import (
 	"fmt"
+	"time"
 
-	"github.com/caarlos0/env/v11"
+	"github.com/bpurdy1/golang-packages/envparse"
 	"github.com/redis/go-redis/v9"
 )
 
 // Config holds the connection parameters
 type Config struct {
 	Addr     string `env:"REDIS_ADDR" envDefault:"localhost:6379"`
-	Password string `env:"REDIS_PASS"`
+	Password string `env:"REDIS_PASSWORD" envAlias:"REDIS_PASS" sensitive:"true"`
 	DB       int    `env:"REDIS_DB" envDefault:"0"`
+
+	// Read-only commands are routed to these replicas when set.
+	ReplicaAddrs  []string      `env:"REDIS_REPLICA_ADDRS" envSeparator:","`
+	ReplicaMaxLag time.Duration `env:"REDIS_REPLICA_MAX_LAG" envDefault:"10s"`
 }
 
 // NewConfig parses environment variables into the Config struct
 func NewConfig() (*Config, error) {
 	cfg := &Config{}
-	if err := env.Parse(cfg); err != nil {
+	if err := envparse.Parse(cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse redis config: %w", err)
 	}
 	return cfg, nil
@@ -30,8 +35,13 @@ type Client interface {
 	Close() error
 }
 
-// NewClient initializes a new Redis client using the provided config
+// NewClient initializes a new Redis client using the provided config. When
+// replicas are configured a ReplicaClient is returned.
 func NewClient(cfg *Config) Client {
+	if len(cfg.ReplicaAddrs) > 0 {
+		return newReplicaClient(cfg)
+	}
+
 	opt := &redis.Options{
 		Addr:     cfg.Addr,
 		Password: cfg.Password,
@@ -0,0 +1,341 @@
+package redisclient
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	replicaCheckInterval = 10 * time.Second
+	replicaCheckTimeout  = 2 * time.Second
+)
+
+// ReplicaClient sends the read-only commands it overrides, such as Get, HGetAll
+// and ZRange, to a replica and everything else to the primary. It is returned
+// by NewClient when REDIS_REPLICA_ADDRS is set.
+//
+// The embedded client is a plain client for the primary, so pipelines,
+// transactions, Watch, Scan and any read not overridden below always run on
+// the primary and keep their usual semantics.
+//
+// Replicas that report a broken link to the primary, or that lag by more than
+// REDIS_REPLICA_MAX_LAG, are taken out of rotation until they catch up.
+// Replica health is checked on start and every 10 seconds after that. Reads
+// go to the primary until a replica has passed a check, and whenever every
+// replica is stale or failing. A read that fails on a replica between checks
+// is retried on the primary.
+type ReplicaClient struct {
+	*redis.Client
+	replicas []*redis.Client
+	maxLag   time.Duration
+
+	healthy atomic.Pointer[[]*redis.Client]
+	next    atomic.Uint64
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newReplicaClient(cfg *Config) *ReplicaClient {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &ReplicaClient{
+		Client: redis.NewClient(&redis.Options{
+			Addr:     cfg.Addr,
+			Password: cfg.Password,
+			DB:       cfg.DB,
+		}),
+		maxLag: cfg.ReplicaMaxLag,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	for _, addr := range cfg.ReplicaAddrs {
+		c.replicas = append(c.replicas, redis.NewClient(&redis.Options{
+			Addr:     addr,
+			Password: cfg.Password,
+			DB:       cfg.DB,
+		}))
+	}
+	c.healthy.Store(&[]*redis.Client{})
+
+	go c.monitor(ctx)
+	return c
+}
+
+// Close stops the health checks and closes the primary and replica
+// connections.
+func (c *ReplicaClient) Close() error {
+	c.cancel()
+	<-c.done
+
+	errs := []error{c.Client.Close()}
+	for _, replica := range c.replicas {
+		errs = append(errs, replica.Close())
+	}
+	return errors.Join(errs...)
+}
+
+func (c *ReplicaClient) monitor(ctx context.Context) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(replicaCheckInterval)
+	defer ticker.Stop()
+	for {
+		c.checkReplicas(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *ReplicaClient) checkReplicas(ctx context.Context) {
+	healthy := make([]*redis.Client, 0, len(c.replicas))
+	for _, replica := range c.replicas {
+		ctx, cancel := context.WithTimeout(ctx, replicaCheckTimeout)
+		if replicaHealthy(ctx, replica, c.maxLag) {
+			healthy = append(healthy, replica)
+		}
+		cancel()
+	}
+	c.healthy.Store(&healthy)
+}
+
+// reader returns the client the next read-only command is sent to, rotating
+// over the healthy replicas.
+func (c *ReplicaClient) reader() *redis.Client {
+	healthy := *c.healthy.Load()
+	if len(healthy) == 0 {
+		return c.Client
+	}
+	return healthy[c.next.Add(1)%uint64(len(healthy))]
+}
+
+// readWithFallback runs cmd on the next reader and, if a replica fails with
+// anything other than redis.Nil, runs it again on the primary.
+func readWithFallback[T redis.Cmder](ctx context.Context, c *ReplicaClient, cmd func(*redis.Client) T) T {
+	r := c.reader()
+	res := cmd(r)
+	if r == c.Client || ctx.Err() != nil {
+		return res
+	}
+	if err := res.Err(); err == nil || errors.Is(err, redis.Nil) {
+		return res
+	}
+	return cmd(c.Client)
+}
+
+// replicaHealthy reports whether a replica is connected to its primary and,
+// if maxLag is positive, has heard from it within maxLag.
+func replicaHealthy(ctx context.Context, replica *redis.Client, maxLag time.Duration) bool {
+	info, err := replica.Info(ctx, "replication").Result()
+	return err == nil && replicationHealthy(info, maxLag)
+}
+
+// replicationHealthy applies the replicaHealthy checks to the output of
+// INFO replication.
+func replicationHealthy(info string, maxLag time.Duration) bool {
+	fields := make(map[string]string)
+	for _, line := range strings.Split(info, "\n") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(line), ":"); ok {
+			fields[k] = v
+		}
+	}
+
+	if fields["role"] != "slave" || fields["master_link_status"] != "up" {
+		return false
+	}
+	if maxLag <= 0 {
+		return true
+	}
+	lastIO, err := strconv.Atoi(fields["master_last_io_seconds_ago"])
+	return err == nil && time.Duration(lastIO)*time.Second <= maxLag
+}
+
+// Read-only commands routed to a replica.
+
+func (c *ReplicaClient) Get(ctx context.Context, key string) *redis.StringCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.StringCmd {
+		return r.Get(ctx, key)
+	})
+}
+
+func (c *ReplicaClient) GetRange(ctx context.Context, key string, start, end int64) *redis.StringCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.StringCmd {
+		return r.GetRange(ctx, key, start, end)
+	})
+}
+
+func (c *ReplicaClient) MGet(ctx context.Context, keys ...string) *redis.SliceCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.SliceCmd {
+		return r.MGet(ctx, keys...)
+	})
+}
+
+func (c *ReplicaClient) StrLen(ctx context.Context, key string) *redis.IntCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.IntCmd {
+		return r.StrLen(ctx, key)
+	})
+}
+
+func (c *ReplicaClient) Exists(ctx context.Context, keys ...string) *redis.IntCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.IntCmd {
+		return r.Exists(ctx, keys...)
+	})
+}
+
+func (c *ReplicaClient) Type(ctx context.Context, key string) *redis.StatusCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.StatusCmd {
+		return r.Type(ctx, key)
+	})
+}
+
+func (c *ReplicaClient) TTL(ctx context.Context, key string) *redis.DurationCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.DurationCmd {
+		return r.TTL(ctx, key)
+	})
+}
+
+func (c *ReplicaClient) PTTL(ctx context.Context, key string) *redis.DurationCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.DurationCmd {
+		return r.PTTL(ctx, key)
+	})
+}
+
+func (c *ReplicaClient) HGet(ctx context.Context, key, field string) *redis.StringCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.StringCmd {
+		return r.HGet(ctx, key, field)
+	})
+}
+
+func (c *ReplicaClient) HMGet(ctx context.Context, key string, fields ...string) *redis.SliceCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.SliceCmd {
+		return r.HMGet(ctx, key, fields...)
+	})
+}
+
+func (c *ReplicaClient) HGetAll(ctx context.Context, key string) *redis.MapStringStringCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.MapStringStringCmd {
+		return r.HGetAll(ctx, key)
+	})
+}
+
+func (c *ReplicaClient) HExists(ctx context.Context, key, field string) *redis.BoolCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.BoolCmd {
+		return r.HExists(ctx, key, field)
+	})
+}
+
+func (c *ReplicaClient) HKeys(ctx context.Context, key string) *redis.StringSliceCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.StringSliceCmd {
+		return r.HKeys(ctx, key)
+	})
+}
+
+func (c *ReplicaClient) HVals(ctx context.Context, key string) *redis.StringSliceCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.StringSliceCmd {
+		return r.HVals(ctx, key)
+	})
+}
+
+func (c *ReplicaClient) HLen(ctx context.Context, key string) *redis.IntCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.IntCmd {
+		return r.HLen(ctx, key)
+	})
+}
+
+func (c *ReplicaClient) LIndex(ctx context.Context, key string, index int64) *redis.StringCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.StringCmd {
+		return r.LIndex(ctx, key, index)
+	})
+}
+
+func (c *ReplicaClient) LLen(ctx context.Context, key string) *redis.IntCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.IntCmd {
+		return r.LLen(ctx, key)
+	})
+}
+
+func (c *ReplicaClient) LRange(ctx context.Context, key string, start, stop int64) *redis.StringSliceCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.StringSliceCmd {
+		return r.LRange(ctx, key, start, stop)
+	})
+}
+
+func (c *ReplicaClient) SCard(ctx context.Context, key string) *redis.IntCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.IntCmd {
+		return r.SCard(ctx, key)
+	})
+}
+
+func (c *ReplicaClient) SIsMember(ctx context.Context, key string, member any) *redis.BoolCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.BoolCmd {
+		return r.SIsMember(ctx, key, member)
+	})
+}
+
+func (c *ReplicaClient) SMIsMember(ctx context.Context, key string, members ...any) *redis.BoolSliceCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.BoolSliceCmd {
+		return r.SMIsMember(ctx, key, members...)
+	})
+}
+
+func (c *ReplicaClient) SMembers(ctx context.Context, key string) *redis.StringSliceCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.StringSliceCmd {
+		return r.SMembers(ctx, key)
+	})
+}
+
+func (c *ReplicaClient) ZCard(ctx context.Context, key string) *redis.IntCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.IntCmd {
+		return r.ZCard(ctx, key)
+	})
+}
+
+func (c *ReplicaClient) ZCount(ctx context.Context, key, min, max string) *redis.IntCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.IntCmd {
+		return r.ZCount(ctx, key, min, max)
+	})
+}
+
+func (c *ReplicaClient) ZScore(ctx context.Context, key, member string) *redis.FloatCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.FloatCmd {
+		return r.ZScore(ctx, key, member)
+	})
+}
+
+func (c *ReplicaClient) ZRank(ctx context.Context, key, member string) *redis.IntCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.IntCmd {
+		return r.ZRank(ctx, key, member)
+	})
+}
+
+func (c *ReplicaClient) ZRange(ctx context.Context, key string, start, stop int64) *redis.StringSliceCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.StringSliceCmd {
+		return r.ZRange(ctx, key, start, stop)
+	})
+}
+
+func (c *ReplicaClient) ZRangeWithScores(ctx context.Context, key string, start, stop int64) *redis.ZSliceCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.ZSliceCmd {
+		return r.ZRangeWithScores(ctx, key, start, stop)
+	})
+}
+
+func (c *ReplicaClient) ZRangeByScore(ctx context.Context, key string, opt *redis.ZRangeBy) *redis.StringSliceCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.StringSliceCmd {
+		return r.ZRangeByScore(ctx, key, opt)
+	})
+}
+
+func (c *ReplicaClient) ZRevRange(ctx context.Context, key string, start, stop int64) *redis.StringSliceCmd {
+	return readWithFallback(ctx, c, func(r *redis.Client) *redis.StringSliceCmd {
+		return r.ZRevRange(ctx, key, start, stop)
+	})
+}
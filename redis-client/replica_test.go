@@ -0,0 +1,137 @@
+package redisclient
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestReplicaClient starts a primary and n replicas, each holding its own
+// value for "k", and marks every replica healthy. Health checks are not run.
+func newTestReplicaClient(t *testing.T, n int) (*ReplicaClient, []*miniredis.Miniredis) {
+	t.Helper()
+	primary := miniredis.RunT(t)
+	primary.Set("k", "primary")
+
+	done := make(chan struct{})
+	close(done)
+	c := &ReplicaClient{
+		Client: redis.NewClient(&redis.Options{Addr: primary.Addr()}),
+		cancel: func() {},
+		done:   done,
+	}
+	var servers []*miniredis.Miniredis
+	for i := range n {
+		m := miniredis.RunT(t)
+		m.Set("k", "replica"+strconv.Itoa(i))
+		servers = append(servers, m)
+		c.replicas = append(c.replicas, redis.NewClient(&redis.Options{
+			Addr:       m.Addr(),
+			MaxRetries: -1,
+		}))
+	}
+	healthy := append([]*redis.Client(nil), c.replicas...)
+	c.healthy.Store(&healthy)
+	t.Cleanup(func() { c.Close() })
+	return c, servers
+}
+
+func TestReplicaClient_RotatesReads(t *testing.T) {
+	c, _ := newTestReplicaClient(t, 2)
+	ctx := context.Background()
+
+	seen := map[string]int{}
+	for range 4 {
+		v, err := c.Get(ctx, "k").Result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen[v]++
+	}
+	if seen["replica0"] != 2 || seen["replica1"] != 2 {
+		t.Errorf("reads = %v, want two per replica", seen)
+	}
+
+	// Writes always go to the primary.
+	if err := c.Set(ctx, "w", "v", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := c.Client.Get(ctx, "w").Result(); err != nil || v != "v" {
+		t.Errorf("primary Get(w) = %q, %v", v, err)
+	}
+}
+
+func TestReplicaClient_NoHealthyReplicas(t *testing.T) {
+	c, _ := newTestReplicaClient(t, 2)
+	c.healthy.Store(&[]*redis.Client{})
+
+	if v, err := c.Get(context.Background(), "k").Result(); err != nil || v != "primary" {
+		t.Errorf("Get = %q, %v, want primary", v, err)
+	}
+}
+
+func TestReplicaClient_FallsBackToPrimary(t *testing.T) {
+	c, servers := newTestReplicaClient(t, 1)
+	ctx := context.Background()
+
+	// A missing key is an answer, not a failure.
+	servers[0].Del("k")
+	if err := c.Get(ctx, "k").Err(); !errors.Is(err, redis.Nil) {
+		t.Errorf("Get of key missing on replica = %v, want redis.Nil", err)
+	}
+
+	servers[0].Close()
+	if v, err := c.Get(ctx, "k").Result(); err != nil || v != "primary" {
+		t.Errorf("Get with replica down = %q, %v, want primary", v, err)
+	}
+	if n, err := c.HLen(ctx, "missing").Result(); err != nil || n != 0 {
+		t.Errorf("HLen with replica down = %d, %v", n, err)
+	}
+}
+
+func TestReplicaClient_CheckReplicas(t *testing.T) {
+	// miniredis does not report replication state, so it never passes the
+	// check.
+	c, _ := newTestReplicaClient(t, 2)
+	c.checkReplicas(context.Background())
+
+	if n := len(*c.healthy.Load()); n != 0 {
+		t.Errorf("%d healthy replicas, want 0", n)
+	}
+	if v, err := c.Get(context.Background(), "k").Result(); err != nil || v != "primary" {
+		t.Errorf("Get = %q, %v, want primary", v, err)
+	}
+}
+
+func TestReplicationHealthy(t *testing.T) {
+	info := func(lastIO string) string {
+		return "# Replication\r\nrole:slave\r\nmaster_link_status:up\r\nmaster_last_io_seconds_ago:" + lastIO + "\r\n"
+	}
+	tests := []struct {
+		name   string
+		info   string
+		maxLag time.Duration
+		want   bool
+	}{
+		{"primary", "# Replication\r\nrole:master\r\nconnected_slaves:1\r\n", 0, false},
+		{"link down", "role:slave\r\nmaster_link_status:down\r\nmaster_last_io_seconds_ago:1\r\n", 0, false},
+		{"no lag limit", info("600"), 0, true},
+		{"within lag", info("2"), 5 * time.Second, true},
+		{"at lag cutoff", info("5"), 5 * time.Second, true},
+		{"past lag cutoff", info("6"), 5 * time.Second, false},
+		{"lag unknown", info("-1x"), 5 * time.Second, false},
+		{"empty", "", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := replicationHealthy(tt.info, tt.maxLag); got != tt.want {
+				t.Errorf("replicationHealthy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
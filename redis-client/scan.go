@@ -0,0 +1,154 @@
+package redisclient
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type scanner struct {
+	count  int64
+	delay  time.Duration
+	dryRun bool
+}
+
+type ScanOption func(*scanner)
+
+// WithScanCount sets the COUNT hint passed to each SCAN call (default: 100).
+func WithScanCount(n int64) ScanOption {
+	return func(s *scanner) {
+		s.count = n
+	}
+}
+
+// WithScanDelay pauses between SCAN batches to limit the load put on Redis.
+func WithScanDelay(d time.Duration) ScanOption {
+	return func(s *scanner) {
+		s.delay = d
+	}
+}
+
+// WithDryRun makes DeleteByPattern and ExpireByPattern only count matching
+// keys without modifying them.
+func WithDryRun() ScanOption {
+	return func(s *scanner) {
+		s.dryRun = true
+	}
+}
+
+func newScanner(opts []ScanOption) *scanner {
+	s := &scanner{count: 100}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// scanNode returns the client every SCAN call of an iteration is sent to. A
+// cursor is only valid on the node that returned it, so a ReplicaClient scans
+// its primary rather than whichever replica is next in rotation.
+func scanNode(client Client) redis.Cmdable {
+	if rc, ok := client.(*ReplicaClient); ok {
+		return rc.Client
+	}
+	return client
+}
+
+// batches calls fn with each batch of keys returned by SCAN until the cursor
+// is exhausted, fn returns an error or ctx is cancelled.
+func (s *scanner) batches(ctx context.Context, client Client, pattern string, fn func(keys []string) error) error {
+	node := scanNode(client)
+
+	var cursor uint64
+	for {
+		keys, next, err := node.Scan(ctx, cursor, pattern, s.count).Result()
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if err := fn(keys); err != nil {
+				return err
+			}
+		}
+
+		cursor = next
+		if cursor == 0 {
+			return nil
+		}
+
+		if s.delay > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(s.delay):
+			}
+		}
+	}
+}
+
+// IterateKeys calls fn for every key matching pattern using SCAN, so it is
+// safe to run against production instances unlike KEYS. The whole iteration
+// runs on one node, the primary for a ReplicaClient. As with SCAN, a key
+// may be visited more than once and keys added during iteration may be missed.
+// Iteration stops at the first error returned by fn.
+func IterateKeys(ctx context.Context, client Client, pattern string, fn func(key string) error, opts ...ScanOption) error {
+	return newScanner(opts).batches(ctx, client, pattern, func(keys []string) error {
+		for _, key := range keys {
+			if err := fn(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteByPattern removes every key matching pattern with UNLINK and returns
+// the number of keys removed. With WithDryRun it returns the number of
+// matching keys instead, which may include duplicates reported by SCAN.
+func DeleteByPattern(ctx context.Context, client Client, pattern string, opts ...ScanOption) (int64, error) {
+	s := newScanner(opts)
+
+	var n int64
+	err := s.batches(ctx, client, pattern, func(keys []string) error {
+		if s.dryRun {
+			n += int64(len(keys))
+			return nil
+		}
+		removed, err := client.Unlink(ctx, keys...).Result()
+		n += removed
+		return err
+	})
+	return n, err
+}
+
+// ExpireByPattern sets ttl on every key matching pattern and returns the
+// number of keys updated. With WithDryRun it returns the number of matching
+// keys instead, which may include duplicates reported by SCAN.
+func ExpireByPattern(ctx context.Context, client Client, pattern string, ttl time.Duration, opts ...ScanOption) (int64, error) {
+	s := newScanner(opts)
+
+	var n int64
+	err := s.batches(ctx, client, pattern, func(keys []string) error {
+		if s.dryRun {
+			n += int64(len(keys))
+			return nil
+		}
+
+		pipe := client.Pipeline()
+		cmds := make([]*redis.BoolCmd, len(keys))
+		for i, key := range keys {
+			cmds[i] = pipe.Expire(ctx, key, ttl)
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
+		}
+		for _, cmd := range cmds {
+			if cmd.Val() {
+				n++
+			}
+		}
+		return nil
+	})
+	return n, err
+}
@@ -0,0 +1,239 @@
+package redisclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// pagingHook makes SCAN honour COUNT, which miniredis ignores by returning
+// every key at once, and records the size of each page returned. Like a Redis
+// cursor, each cursor resumes after the last key returned, so keys removed
+// between calls do not shift later pages.
+type pagingHook struct {
+	pages []int
+	after map[uint64]string
+}
+
+func (h *pagingHook) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (h *pagingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func (h *pagingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		scan, ok := cmd.(*redis.ScanCmd)
+		if !ok {
+			return next(ctx, cmd)
+		}
+		args := cmd.Args()
+		cursor := args[1].(uint64)
+		count := int(args[len(args)-1].(int64))
+		args[1] = uint64(0)
+		if err := next(ctx, cmd); err != nil {
+			return err
+		}
+
+		// miniredis returns the keys sorted.
+		keys, _ := scan.Val()
+		if cursor != 0 {
+			after := h.after[cursor]
+			keys = keys[sort.SearchStrings(keys, after+"\x00"):]
+		}
+		page := keys[:min(count, len(keys))]
+		var nextCursor uint64
+		if len(page) < len(keys) {
+			if h.after == nil {
+				h.after = make(map[uint64]string)
+			}
+			nextCursor = uint64(len(h.after) + 1)
+			h.after[nextCursor] = page[len(page)-1]
+		}
+		scan.SetVal(page, nextCursor)
+		h.pages = append(h.pages, len(page))
+		return nil
+	}
+}
+
+// newScanTestClient returns a client whose SCAN pages like Redis, with n keys
+// "user:0".."user:n-1" and one unrelated key.
+func newScanTestClient(t *testing.T, n int) (Client, *miniredis.Miniredis, *pagingHook) {
+	t.Helper()
+	client, m := newTestClient(t)
+	hook := &pagingHook{}
+	client.(RedisClient).AddHook(hook)
+	for i := range n {
+		m.Set(fmt.Sprintf("user:%d", i), "v")
+	}
+	m.Set("order:1", "v")
+	return client, m, hook
+}
+
+func TestIterateKeys(t *testing.T) {
+	client, _, hook := newScanTestClient(t, 25)
+
+	var keys []string
+	err := IterateKeys(context.Background(), client, "user:*", func(key string) error {
+		keys = append(keys, key)
+		return nil
+	}, WithScanCount(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 25 {
+		t.Errorf("visited %d keys, want 25", len(keys))
+	}
+	for _, key := range keys {
+		if !strings.HasPrefix(key, "user:") {
+			t.Errorf("visited %q, which does not match the pattern", key)
+		}
+	}
+	if fmt.Sprint(hook.pages) != "[10 10 5]" {
+		t.Errorf("SCAN pages = %v, want [10 10 5]", hook.pages)
+	}
+}
+
+func TestIterateKeys_StopsOnError(t *testing.T) {
+	client, _, hook := newScanTestClient(t, 25)
+	errStop := errors.New("stop")
+
+	visited := 0
+	err := IterateKeys(context.Background(), client, "user:*", func(string) error {
+		visited++
+		if visited == 3 {
+			return errStop
+		}
+		return nil
+	}, WithScanCount(10))
+	if !errors.Is(err, errStop) {
+		t.Errorf("IterateKeys = %v, want errStop", err)
+	}
+	if visited != 3 || len(hook.pages) != 1 {
+		t.Errorf("visited %d keys over %d SCAN calls, want 3 over 1", visited, len(hook.pages))
+	}
+}
+
+func TestIterateKeys_CancelDuringDelay(t *testing.T) {
+	client, _, _ := newScanTestClient(t, 25)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := IterateKeys(ctx, client, "user:*", func(string) error {
+		cancel()
+		return nil
+	}, WithScanCount(10), WithScanDelay(time.Hour))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("IterateKeys = %v, want context.Canceled", err)
+	}
+}
+
+func TestDeleteByPattern(t *testing.T) {
+	tests := []struct {
+		name  string
+		keys  int
+		count int64
+		pages string
+	}{
+		{"empty", 0, 10, "[0]"},
+		{"one page", 7, 10, "[7]"},
+		{"exact pages", 20, 10, "[10 10]"},
+		{"partial last page", 25, 10, "[10 10 5]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, m, hook := newScanTestClient(t, tt.keys)
+
+			n, err := DeleteByPattern(context.Background(), client, "user:*", WithScanCount(tt.count))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != int64(tt.keys) {
+				t.Errorf("DeleteByPattern = %d, want %d", n, tt.keys)
+			}
+			if fmt.Sprint(hook.pages) != tt.pages {
+				t.Errorf("SCAN pages = %v, want %s", hook.pages, tt.pages)
+			}
+			if keys := m.Keys(); len(keys) != 1 || keys[0] != "order:1" {
+				t.Errorf("keys left = %v, want [order:1]", keys)
+			}
+		})
+	}
+}
+
+func TestDeleteByPattern_DryRun(t *testing.T) {
+	client, m, _ := newScanTestClient(t, 25)
+
+	n, err := DeleteByPattern(context.Background(), client, "user:*", WithScanCount(10), WithDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 25 {
+		t.Errorf("DeleteByPattern dry run = %d, want 25", n)
+	}
+	if keys := m.Keys(); len(keys) != 26 {
+		t.Errorf("dry run removed keys: %d left, want 26", len(keys))
+	}
+}
+
+func TestExpireByPattern(t *testing.T) {
+	client, m, hook := newScanTestClient(t, 25)
+
+	n, err := ExpireByPattern(context.Background(), client, "user:*", time.Minute, WithScanCount(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 25 {
+		t.Errorf("ExpireByPattern = %d, want 25", n)
+	}
+	if fmt.Sprint(hook.pages) != "[10 10 5]" {
+		t.Errorf("SCAN pages = %v, want [10 10 5]", hook.pages)
+	}
+	if ttl := m.TTL("user:24"); ttl != time.Minute {
+		t.Errorf("TTL(user:24) = %v, want 1m", ttl)
+	}
+	if ttl := m.TTL("order:1"); ttl != 0 {
+		t.Errorf("TTL(order:1) = %v, want none", ttl)
+	}
+}
+
+func TestExpireByPattern_DryRun(t *testing.T) {
+	client, m, _ := newScanTestClient(t, 25)
+
+	n, err := ExpireByPattern(context.Background(), client, "user:*", time.Minute, WithScanCount(10), WithDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 25 {
+		t.Errorf("ExpireByPattern dry run = %d, want 25", n)
+	}
+	if ttl := m.TTL("user:0"); ttl != 0 {
+		t.Errorf("dry run set TTL(user:0) = %v", ttl)
+	}
+}
+
+func TestIterateKeys_ReplicaClientScansPrimary(t *testing.T) {
+	c, _ := newTestReplicaClient(t, 1)
+	ctx := context.Background()
+	if err := c.Set(ctx, "only:primary", "v", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	err := IterateKeys(ctx, c, "only:*", func(key string) error {
+		keys = append(keys, key)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 {
+		t.Errorf("keys = %v, want [only:primary]", keys)
+	}
+}
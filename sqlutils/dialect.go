@@ -0,0 +1,32 @@
+package sqlutils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Dialect identifies the SQL flavour used when building statements.
+type Dialect int
+
+const (
+	Postgres Dialect = iota
+	SQLite
+)
+
+// Placeholder returns the bind parameter for the n-th (1-based) argument.
+func (d Dialect) Placeholder(n int) string {
+	if d == Postgres {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+// QuoteIdent quotes a table or column name. Dotted names such as
+// "auth.users" are quoted per part.
+func (d Dialect) QuoteIdent(name string) string {
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = `"` + strings.ReplaceAll(p, `"`, `""`) + `"`
+	}
+	return strings.Join(parts, ".")
+}
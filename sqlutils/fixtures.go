@@ -0,0 +1,316 @@
+package sqlutils
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type fixtureLoader struct {
+	dialect  Dialect
+	now      time.Time
+	truncate bool
+	cascade  bool
+}
+
+type FixtureOption func(*fixtureLoader)
+
+// WithDialect sets the SQL dialect used for YAML fixtures and truncation
+// (default: Postgres).
+func WithDialect(d Dialect) FixtureOption {
+	return func(l *fixtureLoader) {
+		l.dialect = d
+	}
+}
+
+// WithNow fixes the time returned by the now, ago and fromNow template
+// functions (default: the time LoadFixtures is called).
+func WithNow(t time.Time) FixtureOption {
+	return func(l *fixtureLoader) {
+		l.now = t
+	}
+}
+
+// WithTruncate empties every table referenced by a YAML fixture before any
+// fixture is loaded, see Truncate.
+func WithTruncate() FixtureOption {
+	return func(l *fixtureLoader) {
+		l.truncate = true
+	}
+}
+
+// WithCascade makes WithTruncate truncate with CASCADE on Postgres, see
+// TruncateCascade. It also empties tables no fixture lists.
+func WithCascade() FixtureOption {
+	return func(l *fixtureLoader) {
+		l.cascade = true
+	}
+}
+
+// LoadFixtures executes the .sql, .yaml and .yml files in dir of fsys in
+// lexical order inside a single transaction. Name files with a numeric prefix
+// (001_users.yaml, 002_sessions.sql) to control the order.
+//
+// SQL files are executed as-is. YAML files map table names to rows, and tables
+// are inserted in the order they appear:
+//
+//	users:
+//	  - id: '{{ uuid "alice" }}'
+//	    email: alice@example.com
+//	    created_at: '{{ ago "24h" }}'
+//
+// Every file is a text/template with these functions:
+//
+//	uuid NAME         deterministic UUID derived from NAME, so fixtures can reference each other
+//	now               the load time in RFC 3339 format
+//	ago DURATION      now minus DURATION (e.g. "1h30m")
+//	fromNow DURATION  now plus DURATION
+//
+// Nested YAML values are stored as JSON.
+func LoadFixtures(ctx context.Context, db *sql.DB, fsys fs.FS, dir string, opts ...FixtureOption) error {
+	l := &fixtureLoader{
+		dialect: Postgres,
+		now:     time.Now().UTC(),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+
+	type fixture struct {
+		name   string
+		sql    string
+		tables []fixtureTable
+	}
+	var fixtures []fixture
+	var tables []string
+	for _, e := range entries {
+		ext := path.Ext(e.Name())
+		if e.IsDir() || (ext != ".sql" && ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		name := path.Join(dir, e.Name())
+		content, err := l.render(fsys, name)
+		if err != nil {
+			return err
+		}
+
+		f := fixture{name: name}
+		if ext == ".sql" {
+			f.sql = content
+		} else {
+			if f.tables, err = parseYAMLFixture(content); err != nil {
+				return fmt.Errorf("fixture %s: %w", name, err)
+			}
+			for _, t := range f.tables {
+				if !slices.Contains(tables, t.name) {
+					tables = append(tables, t.name)
+				}
+			}
+		}
+		fixtures = append(fixtures, f)
+	}
+
+	return WithTx(ctx, db, func(tx *sql.Tx) error {
+		if l.truncate && len(tables) > 0 {
+			if err := truncate(ctx, tx, l.dialect, tables, l.cascade); err != nil {
+				return err
+			}
+		}
+		for _, f := range fixtures {
+			if f.sql != "" {
+				if _, err := tx.ExecContext(ctx, f.sql); err != nil {
+					return fmt.Errorf("fixture %s: %w", f.name, err)
+				}
+				continue
+			}
+			for _, t := range f.tables {
+				if err := insertRows(ctx, tx, l.dialect, t); err != nil {
+					return fmt.Errorf("fixture %s: %w", f.name, err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Truncate removes all rows from tables. On Postgres identity columns are
+// reset, and it fails if a table not listed has a foreign key to one that is.
+// Names may be qualified with a schema, e.g. "auth.users".
+func Truncate(ctx context.Context, db *sql.DB, dialect Dialect, tables ...string) error {
+	return WithTx(ctx, db, func(tx *sql.Tx) error {
+		return truncate(ctx, tx, dialect, tables, false)
+	})
+}
+
+// TruncateCascade is like Truncate, but on Postgres it also empties every
+// table with a foreign key to one of tables, recursively, whether listed or
+// not. On SQLite it is the same as Truncate.
+func TruncateCascade(ctx context.Context, db *sql.DB, dialect Dialect, tables ...string) error {
+	return WithTx(ctx, db, func(tx *sql.Tx) error {
+		return truncate(ctx, tx, dialect, tables, true)
+	})
+}
+
+func truncate(ctx context.Context, tx *sql.Tx, dialect Dialect, tables []string, cascade bool) error {
+	if dialect == Postgres {
+		_, err := tx.ExecContext(ctx, buildTruncate(tables, cascade))
+		return err
+	}
+
+	quoted := make([]string, len(tables))
+	for i, t := range tables {
+		quoted[i] = dialect.QuoteIdent(t)
+	}
+	// SQLite has no TRUNCATE; delete in reverse so children go before parents.
+	for i := len(quoted) - 1; i >= 0; i-- {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+quoted[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildTruncate returns the Postgres TRUNCATE statement for tables.
+func buildTruncate(tables []string, cascade bool) string {
+	quoted := make([]string, len(tables))
+	for i, t := range tables {
+		quoted[i] = Postgres.QuoteIdent(t)
+	}
+	query := "TRUNCATE TABLE " + strings.Join(quoted, ", ") + " RESTART IDENTITY"
+	if cascade {
+		query += " CASCADE"
+	}
+	return query
+}
+
+func (l *fixtureLoader) render(fsys fs.FS, name string) (string, error) {
+	b, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return "", err
+	}
+
+	tmpl, err := template.New(name).Funcs(template.FuncMap{
+		"uuid": fixtureUUID,
+		"now": func() string {
+			return l.now.Format(time.RFC3339Nano)
+		},
+		"ago": func(d string) (string, error) {
+			dur, err := time.ParseDuration(d)
+			return l.now.Add(-dur).Format(time.RFC3339Nano), err
+		},
+		"fromNow": func(d string) (string, error) {
+			dur, err := time.ParseDuration(d)
+			return l.now.Add(dur).Format(time.RFC3339Nano), err
+		},
+	}).Parse(string(b))
+	if err != nil {
+		return "", fmt.Errorf("fixture %s: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, nil); err != nil {
+		return "", fmt.Errorf("fixture %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// fixtureUUID derives a version 5 style UUID from name.
+func fixtureUUID(name string) string {
+	h := sha1.Sum([]byte(name))
+	h[6] = (h[6] & 0x0f) | 0x50
+	h[8] = (h[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", h[0:4], h[4:6], h[6:8], h[8:10], h[10:16])
+}
+
+type fixtureTable struct {
+	name string
+	rows []map[string]any
+}
+
+// parseYAMLFixture decodes a table -> rows document, keeping tables in
+// document order so parents can be listed before children.
+func parseYAMLFixture(content string) ([]fixtureTable, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("expected a mapping of table names to rows")
+	}
+
+	tables := make([]fixtureTable, 0, len(root.Content)/2)
+	for i := 0; i < len(root.Content); i += 2 {
+		t := fixtureTable{name: root.Content[i].Value}
+		if err := root.Content[i+1].Decode(&t.rows); err != nil {
+			return nil, fmt.Errorf("table %s: %w", t.name, err)
+		}
+		tables = append(tables, t)
+	}
+	return tables, nil
+}
+
+func insertRows(ctx context.Context, tx *sql.Tx, dialect Dialect, t fixtureTable) error {
+	for _, row := range t.rows {
+		query, args, err := buildInsert(dialect, t.name, row)
+		if err != nil {
+			return fmt.Errorf("table %s: %w", t.name, err)
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("table %s: %w", t.name, err)
+		}
+	}
+	return nil
+}
+
+func buildInsert(dialect Dialect, table string, row map[string]any) (string, []any, error) {
+	columns := make([]string, 0, len(row))
+	for c := range row {
+		columns = append(columns, c)
+	}
+	slices.Sort(columns)
+
+	quoted := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	args := make([]any, len(columns))
+	for i, c := range columns {
+		quoted[i] = dialect.QuoteIdent(c)
+		placeholders[i] = dialect.Placeholder(i + 1)
+
+		switch v := row[c].(type) {
+		case map[string]any, []any:
+			b, err := json.Marshal(v)
+			if err != nil {
+				return "", nil, fmt.Errorf("column %s: %w", c, err)
+			}
+			args[i] = string(b)
+		default:
+			args[i] = v
+		}
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		dialect.QuoteIdent(table), strings.Join(quoted, ", "), strings.Join(placeholders, ", "))
+	return query, args, nil
+}
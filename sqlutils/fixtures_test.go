@@ -0,0 +1,137 @@
+package sqlutils
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestFixtureUUID(t *testing.T) {
+	a := fixtureUUID("alice")
+	if a != fixtureUUID("alice") {
+		t.Error("fixtureUUID is not deterministic")
+	}
+	if a == fixtureUUID("bob") {
+		t.Error("fixtureUUID returned the same UUID for different names")
+	}
+
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	if !uuid.MatchString(a) {
+		t.Errorf("fixtureUUID = %s, want a version 5 UUID", a)
+	}
+}
+
+func TestParseYAMLFixture(t *testing.T) {
+	tables, err := parseYAMLFixture(`
+users:
+  - id: 1
+    email: alice@example.com
+    tags: [admin]
+sessions:
+  - user_id: 1
+accounts: []
+`)
+	if err != nil {
+		t.Fatalf("parseYAMLFixture: %v", err)
+	}
+
+	want := []fixtureTable{
+		{name: "users", rows: []map[string]any{{"id": 1, "email": "alice@example.com", "tags": []any{"admin"}}}},
+		{name: "sessions", rows: []map[string]any{{"user_id": 1}}},
+		{name: "accounts", rows: []map[string]any{}},
+	}
+	if !reflect.DeepEqual(tables, want) {
+		t.Errorf("tables = %#v\nwant     %#v", tables, want)
+	}
+}
+
+func TestParseYAMLFixture_Invalid(t *testing.T) {
+	for _, content := range []string{"- users", "users: alice", "users: [1, 2]", "users: {"} {
+		if _, err := parseYAMLFixture(content); err == nil {
+			t.Errorf("parseYAMLFixture(%q): expected error", content)
+		}
+	}
+
+	tables, err := parseYAMLFixture("")
+	if err != nil || tables != nil {
+		t.Errorf("parseYAMLFixture(\"\") = %v, %v; want nil, nil", tables, err)
+	}
+}
+
+func TestBuildInsert(t *testing.T) {
+	row := map[string]any{
+		"id":       1,
+		"email":    "alice@example.com",
+		"settings": map[string]any{"theme": "dark"},
+		"tags":     []any{"admin"},
+	}
+
+	tests := []struct {
+		name      string
+		dialect   Dialect
+		table     string
+		wantQuery string
+	}{
+		{
+			name:      "postgres",
+			dialect:   Postgres,
+			table:     "users",
+			wantQuery: `INSERT INTO "users" ("email", "id", "settings", "tags") VALUES ($1, $2, $3, $4)`,
+		},
+		{
+			name:      "sqlite",
+			dialect:   SQLite,
+			table:     "users",
+			wantQuery: `INSERT INTO "users" ("email", "id", "settings", "tags") VALUES (?, ?, ?, ?)`,
+		},
+		{
+			name:      "schema qualified",
+			dialect:   Postgres,
+			table:     "auth.users",
+			wantQuery: `INSERT INTO "auth"."users" ("email", "id", "settings", "tags") VALUES ($1, $2, $3, $4)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := buildInsert(tt.dialect, tt.table, row)
+			if err != nil {
+				t.Fatalf("buildInsert: %v", err)
+			}
+			if query != tt.wantQuery {
+				t.Errorf("query = %s\nwant    %s", query, tt.wantQuery)
+			}
+			wantArgs := []any{"alice@example.com", 1, `{"theme":"dark"}`, `["admin"]`}
+			if !reflect.DeepEqual(args, wantArgs) {
+				t.Errorf("args = %#v, want %#v", args, wantArgs)
+			}
+		})
+	}
+}
+
+func TestBuildTruncate(t *testing.T) {
+	tables := []string{"users", "auth.sessions"}
+
+	want := `TRUNCATE TABLE "users", "auth"."sessions" RESTART IDENTITY`
+	if got := buildTruncate(tables, false); got != want {
+		t.Errorf("buildTruncate = %s, want %s", got, want)
+	}
+	if got := buildTruncate(tables, true); got != want+" CASCADE" {
+		t.Errorf("buildTruncate cascade = %s, want %s CASCADE", got, want)
+	}
+}
+
+func TestQuoteIdent(t *testing.T) {
+	tests := map[string]string{
+		"users":         `"users"`,
+		"auth.users":    `"auth"."users"`,
+		`we"ird`:        `"we""ird"`,
+		"public.a.b":    `"public"."a"."b"`,
+		"Mixed.Case_id": `"Mixed"."Case_id"`,
+	}
+	for in, want := range tests {
+		if got := Postgres.QuoteIdent(in); got != want {
+			t.Errorf("QuoteIdent(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
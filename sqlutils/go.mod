@@ -1,3 +1,8 @@
 module github.com/bpurdy1/golang-packages/sqlutils
 
 go 1.25.6
+
+require (
+	github.com/DATA-DOG/go-sqlmock v1.5.2
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,191 @@
+package sqlutils
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrCursorMismatch = errors.New("sqlutils: cursor does not match the sort columns")
+
+// cursorValue is one typed value of a cursor. Values are kept as strings so
+// integers above 2^53 survive the JSON round trip.
+type cursorValue struct {
+	Type  string `json:"t"`
+	Value string `json:"v,omitempty"`
+}
+
+// EncodeCursor serializes the sort key values of the last row on a page into
+// an opaque, URL-safe cursor. Each value keeps its type, so DecodeCursor
+// returns values usable as KeysetPage.After. Supported are integers, floats,
+// strings, bools, []byte, time.Time, nil and driver.Valuer types that produce
+// one of these (such as UUIDs).
+//
+//	next, err := sqlutils.EncodeCursor(last.CreatedAt, last.ID)
+func EncodeCursor(values ...any) (string, error) {
+	encoded := make([]cursorValue, len(values))
+	for i, v := range values {
+		cv, err := encodeCursorValue(v)
+		if err != nil {
+			return "", err
+		}
+		encoded[i] = cv
+	}
+	b, err := json.Marshal(encoded)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func encodeCursorValue(v any) (cursorValue, error) {
+	if valuer, ok := v.(driver.Valuer); ok {
+		dv, err := valuer.Value()
+		if err != nil {
+			return cursorValue{}, err
+		}
+		v = dv
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return cursorValue{Type: "n"}, nil
+	case string:
+		return cursorValue{Type: "s", Value: v}, nil
+	case bool:
+		return cursorValue{Type: "b", Value: strconv.FormatBool(v)}, nil
+	case []byte:
+		return cursorValue{Type: "x", Value: base64.RawURLEncoding.EncodeToString(v)}, nil
+	case time.Time:
+		return cursorValue{Type: "t", Value: v.Format(time.RFC3339Nano)}, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cursorValue{Type: "i", Value: strconv.FormatInt(rv.Int(), 10)}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return cursorValue{Type: "u", Value: strconv.FormatUint(rv.Uint(), 10)}, nil
+	case reflect.Float32, reflect.Float64:
+		return cursorValue{Type: "f", Value: strconv.FormatFloat(rv.Float(), 'g', -1, 64)}, nil
+	}
+	return cursorValue{}, fmt.Errorf("sqlutils: unsupported cursor value type %T", v)
+}
+
+// DecodeCursor parses a cursor produced by EncodeCursor. Integers decode as
+// int64 (uint64 if unsigned), floats as float64 and times as time.Time.
+func DecodeCursor(cursor string) ([]any, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	var encoded []cursorValue
+	if err := json.Unmarshal(b, &encoded); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	values := make([]any, len(encoded))
+	for i, cv := range encoded {
+		v, err := decodeCursorValue(cv)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %w", err)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func decodeCursorValue(cv cursorValue) (any, error) {
+	switch cv.Type {
+	case "n":
+		return nil, nil
+	case "s":
+		return cv.Value, nil
+	case "b":
+		return strconv.ParseBool(cv.Value)
+	case "x":
+		return base64.RawURLEncoding.DecodeString(cv.Value)
+	case "t":
+		return time.Parse(time.RFC3339Nano, cv.Value)
+	case "i":
+		return strconv.ParseInt(cv.Value, 10, 64)
+	case "u":
+		return strconv.ParseUint(cv.Value, 10, 64)
+	case "f":
+		return strconv.ParseFloat(cv.Value, 64)
+	}
+	return nil, fmt.Errorf("unknown value type %q", cv.Type)
+}
+
+// KeysetPage describes one page of a keyset-paginated query.
+type KeysetPage struct {
+	// Columns is the sort key, most significant first. It must be unique per
+	// row, so end it with a primary key.
+	Columns []string
+	Desc    bool
+	Limit   int
+	// After holds the Columns values of the last row of the previous page, as
+	// returned by DecodeCursor, or nil for the first page.
+	After []any
+}
+
+// BuildKeysetQuery wraps base so it returns the page of rows following
+// page.After:
+//
+//	SELECT * FROM (base) AS keyset WHERE (created_at, id) > ($2, $3)
+//	ORDER BY created_at, id LIMIT 50
+//
+// args are the arguments of base; the returned arguments append page.After.
+// Columns must be column names in the output of base. Row value comparisons
+// require Postgres or SQLite 3.15+. It returns ErrCursorMismatch if After is
+// set but does not hold one value per column, e.g. for a cursor from another
+// endpoint.
+func BuildKeysetQuery(dialect Dialect, base string, args []any, page KeysetPage) (string, []any, error) {
+	if len(page.After) > 0 && len(page.After) != len(page.Columns) {
+		return "", nil, ErrCursorMismatch
+	}
+
+	cols := make([]string, len(page.Columns))
+	order := make([]string, len(page.Columns))
+	for i, c := range page.Columns {
+		cols[i] = dialect.QuoteIdent(c)
+		order[i] = cols[i]
+		if page.Desc {
+			order[i] += " DESC"
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("SELECT * FROM (")
+	sb.WriteString(base)
+	sb.WriteString(") AS keyset")
+
+	allArgs := append([]any(nil), args...)
+	if len(page.After) > 0 {
+		placeholders := make([]string, len(page.After))
+		for i, v := range page.After {
+			allArgs = append(allArgs, v)
+			placeholders[i] = dialect.Placeholder(len(allArgs))
+		}
+
+		op := ">"
+		if page.Desc {
+			op = "<"
+		}
+		fmt.Fprintf(&sb, " WHERE (%s) %s (%s)", strings.Join(cols, ", "), op, strings.Join(placeholders, ", "))
+	}
+
+	sb.WriteString(" ORDER BY ")
+	sb.WriteString(strings.Join(order, ", "))
+	if page.Limit > 0 {
+		sb.WriteString(" LIMIT ")
+		sb.WriteString(strconv.Itoa(page.Limit))
+	}
+	return sb.String(), allArgs, nil
+}
@@ -0,0 +1,106 @@
+package sqlutils
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	created := time.Date(2026, 3, 1, 12, 30, 0, 123456789, time.UTC)
+	values := []any{created, int64(1<<62 + 1), uint32(7), 1.5, "alice", true, []byte{0xff, 0x00}, nil}
+
+	cursor, err := EncodeCursor(values...)
+	if err != nil {
+		t.Fatalf("EncodeCursor: %v", err)
+	}
+	got, err := DecodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("DecodeCursor: %v", err)
+	}
+
+	want := []any{created, int64(1<<62 + 1), uint64(7), 1.5, "alice", true, []byte{0xff, 0x00}, nil}
+	if len(got) != len(want) {
+		t.Fatalf("got %d values, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if tm, ok := want[i].(time.Time); ok {
+			if !tm.Equal(got[i].(time.Time)) {
+				t.Errorf("value %d = %v, want %v", i, got[i], tm)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("value %d = %#v, want %#v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	for _, cursor := range []string{"!!!", "bm90IGpzb24", "W3sidCI6InoifV0"} {
+		if _, err := DecodeCursor(cursor); err == nil {
+			t.Errorf("DecodeCursor(%q): expected error", cursor)
+		}
+	}
+	if _, err := EncodeCursor(struct{}{}); err == nil {
+		t.Error("EncodeCursor: expected error for unsupported type")
+	}
+}
+
+func TestBuildKeysetQuery(t *testing.T) {
+	created := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		dialect   Dialect
+		args      []any
+		page      KeysetPage
+		wantQuery string
+		wantArgs  []any
+		wantErr   error
+	}{
+		{
+			name:      "first page",
+			dialect:   Postgres,
+			page:      KeysetPage{Columns: []string{"id"}, Limit: 10},
+			wantQuery: `SELECT * FROM (SELECT * FROM users) AS keyset ORDER BY "id" LIMIT 10`,
+		},
+		{
+			name:      "after with base args",
+			dialect:   Postgres,
+			args:      []any{"active"},
+			page:      KeysetPage{Columns: []string{"created_at", "id"}, Limit: 50, After: []any{created, int64(42)}},
+			wantQuery: `SELECT * FROM (SELECT * FROM users) AS keyset WHERE ("created_at", "id") > ($2, $3) ORDER BY "created_at", "id" LIMIT 50`,
+			wantArgs:  []any{"active", created, int64(42)},
+		},
+		{
+			name:      "descending sqlite",
+			dialect:   SQLite,
+			page:      KeysetPage{Columns: []string{"id"}, Desc: true, After: []any{int64(5)}},
+			wantQuery: `SELECT * FROM (SELECT * FROM users) AS keyset WHERE ("id") < (?) ORDER BY "id" DESC`,
+			wantArgs:  []any{int64(5)},
+		},
+		{
+			name:    "cursor for other columns",
+			dialect: Postgres,
+			page:    KeysetPage{Columns: []string{"created_at", "id"}, After: []any{int64(42)}},
+			wantErr: ErrCursorMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := BuildKeysetQuery(tt.dialect, "SELECT * FROM users", tt.args, tt.page)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if query != tt.wantQuery {
+				t.Errorf("query = %s\nwant    %s", query, tt.wantQuery)
+			}
+			if len(args) != len(tt.wantArgs) || (len(args) > 0 && !reflect.DeepEqual(args, tt.wantArgs)) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
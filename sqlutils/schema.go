@@ -0,0 +1,149 @@
+package sqlutils
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// Querier is satisfied by *sql.DB, *sql.Tx and *sql.Conn.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Column describes a table column as reported by the database.
+type Column struct {
+	Name string
+	// Type is the declared type, e.g. "integer" or "character varying" on
+	// Postgres and "INTEGER" or "TEXT" on SQLite.
+	Type     string
+	Nullable bool
+	// Default is the default expression, or nil if the column has none.
+	Default    *string
+	PrimaryKey bool
+	// Position is the 1-based ordinal position in the table.
+	Position int
+}
+
+const (
+	pgListTables = `SELECT table_name FROM information_schema.tables
+WHERE table_schema = current_schema() AND table_type = 'BASE TABLE'
+ORDER BY table_name`
+
+	pgTableExists = `SELECT EXISTS (SELECT 1 FROM information_schema.tables
+WHERE table_schema = COALESCE(NULLIF($1, ''), current_schema()) AND table_name = $2
+	AND table_type = 'BASE TABLE')`
+
+	pgListColumns = `SELECT c.column_name, c.data_type, c.is_nullable = 'YES', c.column_default,
+	EXISTS (
+		SELECT 1 FROM information_schema.table_constraints tc
+		JOIN information_schema.key_column_usage k
+			ON k.constraint_schema = tc.constraint_schema AND k.constraint_name = tc.constraint_name
+		WHERE tc.constraint_type = 'PRIMARY KEY'
+			AND tc.table_schema = c.table_schema AND tc.table_name = c.table_name
+			AND k.column_name = c.column_name
+	),
+	c.ordinal_position
+FROM information_schema.columns c
+WHERE c.table_schema = COALESCE(NULLIF($1, ''), current_schema()) AND c.table_name = $2
+ORDER BY c.ordinal_position`
+
+	sqliteListTables = `SELECT name FROM sqlite_master
+WHERE type = 'table' AND name NOT LIKE 'sqlite_%'
+ORDER BY name`
+
+	sqliteTableExists = `SELECT EXISTS (SELECT 1 FROM pragma_table_list
+WHERE schema = ? AND name = ? AND type = 'table' AND name NOT LIKE 'sqlite_%')`
+
+	sqliteListColumns = `SELECT name, type, "notnull" = 0, dflt_value, pk > 0, cid + 1
+FROM pragma_table_info(?, ?)
+ORDER BY cid`
+)
+
+// ListTables returns the names of the tables in the current schema (Postgres)
+// or main database (SQLite), sorted by name.
+func ListTables(ctx context.Context, db Querier, dialect Dialect) ([]string, error) {
+	query := pgListTables
+	if dialect == SQLite {
+		query = sqliteListTables
+	}
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		tables = append(tables, name)
+	}
+	return tables, rows.Err()
+}
+
+// TableExists reports whether table exists. Like ListTables it only considers
+// base tables, not views. The name may be qualified with a schema, e.g.
+// "auth.users".
+func TableExists(ctx context.Context, db Querier, dialect Dialect, table string) (bool, error) {
+	schema, name := splitTable(table)
+	query := pgTableExists
+	if dialect == SQLite {
+		if schema == "" {
+			schema = "main"
+		}
+		query = sqliteTableExists
+	}
+
+	var exists bool
+	err := db.QueryRowContext(ctx, query, schema, name).Scan(&exists)
+	return exists, err
+}
+
+// ListColumns returns the columns of table in ordinal order, or none if the
+// table does not exist. The name may be qualified with a schema.
+func ListColumns(ctx context.Context, db Querier, dialect Dialect, table string) ([]Column, error) {
+	schema, name := splitTable(table)
+	query, args := pgListColumns, []any{schema, name}
+	if dialect == SQLite {
+		if schema == "" {
+			schema = "main"
+		}
+		query, args = sqliteListColumns, []any{name, schema}
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []Column
+	for rows.Next() {
+		var (
+			c   Column
+			def sql.NullString
+		)
+		if err := rows.Scan(&c.Name, &c.Type, &c.Nullable, &def, &c.PrimaryKey, &c.Position); err != nil {
+			return nil, err
+		}
+		if def.Valid {
+			c.Default = &def.String
+		}
+		columns = append(columns, c)
+	}
+	return columns, rows.Err()
+}
+
+// splitTable splits "schema.table" into its parts; schema is empty for an
+// unqualified name.
+func splitTable(table string) (schema, name string) {
+	if i := strings.LastIndex(table, "."); i >= 0 {
+		return table[:i], table[i+1:]
+	}
+	return "", table
+}
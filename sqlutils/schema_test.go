@@ -0,0 +1,137 @@
+package sqlutils
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func newSchemaMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		db.Close()
+	})
+	return db, mock
+}
+
+func TestListTables(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		query   string
+	}{
+		{"postgres", Postgres, pgListTables},
+		{"sqlite", SQLite, sqliteListTables},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newSchemaMock(t)
+			mock.ExpectQuery(tt.query).WillReturnRows(
+				sqlmock.NewRows([]string{"name"}).AddRow("orders").AddRow("users"))
+
+			tables, err := ListTables(context.Background(), db, tt.dialect)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := []string{"orders", "users"}; !reflect.DeepEqual(tables, want) {
+				t.Errorf("ListTables = %v, want %v", tables, want)
+			}
+		})
+	}
+}
+
+func TestTableExists(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  Dialect
+		table    string
+		query    string
+		wantArgs []any
+	}{
+		{"postgres", Postgres, "users", pgTableExists, []any{"", "users"}},
+		{"postgres schema", Postgres, "auth.users", pgTableExists, []any{"auth", "users"}},
+		{"sqlite", SQLite, "users", sqliteTableExists, []any{"main", "users"}},
+		{"sqlite attached", SQLite, "audit.events", sqliteTableExists, []any{"audit", "events"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newSchemaMock(t)
+			mock.ExpectQuery(tt.query).WithArgs(tt.wantArgs[0], tt.wantArgs[1]).
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+			ok, err := TableExists(context.Background(), db, tt.dialect, tt.table)
+			if err != nil || !ok {
+				t.Errorf("TableExists = %v, %v, want true", ok, err)
+			}
+		})
+	}
+}
+
+func TestListColumns(t *testing.T) {
+	cols := []string{"name", "type", "nullable", "default", "pk", "position"}
+	def := "now()"
+	want := []Column{
+		{Name: "id", Type: "integer", PrimaryKey: true, Position: 1},
+		{Name: "created_at", Type: "timestamp with time zone", Default: &def, Position: 2},
+		{Name: "deleted_at", Type: "timestamp with time zone", Nullable: true, Position: 3},
+	}
+
+	tests := []struct {
+		name     string
+		dialect  Dialect
+		table    string
+		query    string
+		wantArgs []any
+	}{
+		{"postgres", Postgres, "users", pgListColumns, []any{"", "users"}},
+		{"postgres schema", Postgres, "auth.users", pgListColumns, []any{"auth", "users"}},
+		{"sqlite", SQLite, "users", sqliteListColumns, []any{"users", "main"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newSchemaMock(t)
+			mock.ExpectQuery(tt.query).WithArgs(tt.wantArgs[0], tt.wantArgs[1]).WillReturnRows(
+				sqlmock.NewRows(cols).
+					AddRow("id", "integer", false, nil, true, 1).
+					AddRow("created_at", "timestamp with time zone", false, "now()", false, 2).
+					AddRow("deleted_at", "timestamp with time zone", true, nil, false, 3))
+
+			got, err := ListColumns(context.Background(), db, tt.dialect, tt.table)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ListColumns = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestListColumns_InTx(t *testing.T) {
+	db, mock := newSchemaMock(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(pgListColumns).WithArgs("", "missing").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "type", "nullable", "default", "pk", "position"}))
+	mock.ExpectRollback()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	cols, err := ListColumns(context.Background(), tx, Postgres, "missing")
+	if err != nil || len(cols) != 0 {
+		t.Errorf("ListColumns = %v, %v, want no columns", cols, err)
+	}
+}
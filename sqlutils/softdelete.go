@@ -0,0 +1,91 @@
+package sqlutils
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// DeletedAtColumn is the nullable timestamp column marking a row as soft
+// deleted. Tables following the convention keep deleted rows with
+// deleted_at set instead of removing them.
+const DeletedAtColumn = "deleted_at"
+
+// Execer is satisfied by *sql.DB, *sql.Tx and *sql.Conn.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// NotDeleted returns the condition matching live rows, for appending to a
+// WHERE clause. alias qualifies the column when the query joins several
+// soft-deleted tables; pass "" for none.
+//
+//	q := "SELECT u.id FROM users u JOIN orgs o ON o.id = u.org_id WHERE " +
+//		sqlutils.NotDeleted(sqlutils.Postgres, "u") + " AND " + sqlutils.NotDeleted(sqlutils.Postgres, "o")
+func NotDeleted(dialect Dialect, alias string) string {
+	col := dialect.QuoteIdent(DeletedAtColumn)
+	if alias != "" {
+		col = dialect.QuoteIdent(alias) + "." + col
+	}
+	return col + " IS NULL"
+}
+
+// BuildSoftDelete returns a statement marking the live row whose keyColumn
+// equals the second argument as deleted at the time given as the first.
+//
+//	UPDATE "users" SET "deleted_at" = $1 WHERE "id" = $2 AND "deleted_at" IS NULL
+func BuildSoftDelete(dialect Dialect, table, keyColumn string) string {
+	return fmt.Sprintf("UPDATE %s SET %s = %s WHERE %s = %s AND %s",
+		dialect.QuoteIdent(table), dialect.QuoteIdent(DeletedAtColumn), dialect.Placeholder(1),
+		dialect.QuoteIdent(keyColumn), dialect.Placeholder(2), NotDeleted(dialect, ""))
+}
+
+// BuildRestore returns a statement undeleting the row whose keyColumn equals
+// the first argument.
+//
+//	UPDATE "users" SET "deleted_at" = NULL WHERE "id" = $1 AND "deleted_at" IS NOT NULL
+func BuildRestore(dialect Dialect, table, keyColumn string) string {
+	col := dialect.QuoteIdent(DeletedAtColumn)
+	return fmt.Sprintf("UPDATE %s SET %s = NULL WHERE %s = %s AND %s IS NOT NULL",
+		dialect.QuoteIdent(table), col, dialect.QuoteIdent(keyColumn), dialect.Placeholder(1), col)
+}
+
+// SoftDelete marks the row of table whose keyColumn equals key as deleted.
+// It returns sql.ErrNoRows if there is no such live row.
+func SoftDelete(ctx context.Context, db Execer, dialect Dialect, table, keyColumn string, key any) error {
+	return execOne(ctx, db, BuildSoftDelete(dialect, table, keyColumn), time.Now().UTC(), key)
+}
+
+// Restore undeletes the row of table whose keyColumn equals key. It returns
+// sql.ErrNoRows if there is no such deleted row.
+func Restore(ctx context.Context, db Execer, dialect Dialect, table, keyColumn string, key any) error {
+	return execOne(ctx, db, BuildRestore(dialect, table, keyColumn), key)
+}
+
+// PurgeDeleted permanently removes rows of table soft deleted before cutoff
+// and returns how many were removed.
+func PurgeDeleted(ctx context.Context, db Execer, dialect Dialect, table string, cutoff time.Time) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s < %s",
+		dialect.QuoteIdent(table), dialect.QuoteIdent(DeletedAtColumn), dialect.Placeholder(1))
+	result, err := db.ExecContext(ctx, query, cutoff.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func execOne(ctx context.Context, db Execer, query string, args ...any) error {
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
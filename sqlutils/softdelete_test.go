@@ -0,0 +1,64 @@
+package sqlutils
+
+import "testing"
+
+func TestNotDeleted(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		alias   string
+		want    string
+	}{
+		{"postgres", Postgres, "", `"deleted_at" IS NULL`},
+		{"postgres alias", Postgres, "u", `"u"."deleted_at" IS NULL`},
+		{"sqlite", SQLite, "", `"deleted_at" IS NULL`},
+		{"sqlite alias", SQLite, "o", `"o"."deleted_at" IS NULL`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NotDeleted(tt.dialect, tt.alias); got != tt.want {
+				t.Errorf("NotDeleted() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildSoftDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		table   string
+		want    string
+	}{
+		{"postgres", Postgres, "users", `UPDATE "users" SET "deleted_at" = $1 WHERE "id" = $2 AND "deleted_at" IS NULL`},
+		{"postgres schema", Postgres, "auth.users", `UPDATE "auth"."users" SET "deleted_at" = $1 WHERE "id" = $2 AND "deleted_at" IS NULL`},
+		{"sqlite", SQLite, "users", `UPDATE "users" SET "deleted_at" = ? WHERE "id" = ? AND "deleted_at" IS NULL`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BuildSoftDelete(tt.dialect, tt.table, "id"); got != tt.want {
+				t.Errorf("BuildSoftDelete() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildRestore(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		table   string
+		want    string
+	}{
+		{"postgres", Postgres, "users", `UPDATE "users" SET "deleted_at" = NULL WHERE "id" = $1 AND "deleted_at" IS NOT NULL`},
+		{"postgres schema", Postgres, "auth.users", `UPDATE "auth"."users" SET "deleted_at" = NULL WHERE "id" = $1 AND "deleted_at" IS NOT NULL`},
+		{"sqlite", SQLite, "users", `UPDATE "users" SET "deleted_at" = NULL WHERE "id" = ? AND "deleted_at" IS NOT NULL`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BuildRestore(tt.dialect, tt.table, "id"); got != tt.want {
+				t.Errorf("BuildRestore() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package waitgroup
+
+import (
+	"context"
+	"sync"
+)
+
+// Group is a drop-in replacement for golang.org/x/sync/errgroup.Group backed
+// by a LimitWaitGroup, so code written against errgroup can use this package's
+// options such as WithRecover and WithPanicErrors.
+//
+//	g, ctx := waitgroup.WithContext(ctx, waitgroup.WithPanicErrors())
+//	g.SetLimit(8)
+//	for _, url := range urls {
+//		g.Go(func() error { return fetch(ctx, url) })
+//	}
+//	err := g.Wait()
+type Group struct {
+	lwg    *LimitWaitGroup
+	cancel context.CancelCauseFunc
+
+	errOnce sync.Once
+	err     error
+}
+
+// NewGroup creates a Group with no limit on active goroutines.
+func NewGroup(opts ...Option) *Group {
+	lwg := &LimitWaitGroup{}
+	for _, opt := range opts {
+		opt(lwg)
+	}
+	return &Group{lwg: lwg}
+}
+
+// WithContext creates a Group and a derived context that is cancelled the
+// first time a function passed to Go returns an error, or when Wait returns.
+func WithContext(ctx context.Context, opts ...Option) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	g := NewGroup(opts...)
+	g.cancel = cancel
+	return g, ctx
+}
+
+// Go runs f in a new goroutine, blocking until the limit allows it to start.
+// The first non-nil error is returned by Wait and cancels the Group's context.
+func (g *Group) Go(f func() error) {
+	g.lwg.addOne()
+	g.lwg.start(g.wrap(f))
+}
+
+// TryGo runs f in a new goroutine only if the limit allows it to start
+// immediately, and reports whether it did.
+func (g *Group) TryGo(f func() error) bool {
+	if !g.lwg.tryAdd() {
+		return false
+	}
+	g.lwg.start(g.wrap(f))
+	return true
+}
+
+// SetLimit limits the number of active goroutines to n. A negative n removes
+// the limit. As with errgroup, a limit of 0 makes Go block forever and TryGo
+// return false. It must not be called while goroutines are active.
+func (g *Group) SetLimit(n int) {
+	if n < 0 {
+		g.lwg.limit = nil
+		return
+	}
+	if active := len(g.lwg.limit); active != 0 {
+		panic("waitgroup: SetLimit called while goroutines are active")
+	}
+	g.lwg.limit = make(chan struct{}, n)
+}
+
+// Wait blocks until every function passed to Go has returned, then returns
+// the first error. With WithPanicErrors, recovered panics are returned when no
+// function failed.
+func (g *Group) Wait() error {
+	panicErr := g.lwg.WaitErr()
+	if g.cancel != nil {
+		g.cancel(g.err)
+	}
+	if g.err != nil {
+		return g.err
+	}
+	return panicErr
+}
+
+func (g *Group) wrap(f func() error) func() {
+	return func() {
+		if err := f(); err != nil {
+			g.errOnce.Do(func() {
+				g.err = err
+				if g.cancel != nil {
+					g.cancel(err)
+				}
+			})
+		}
+	}
+}
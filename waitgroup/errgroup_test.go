@@ -0,0 +1,109 @@
+package waitgroup
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup_FirstErrorCancelsContext(t *testing.T) {
+	errFirst := errors.New("first")
+	g, ctx := WithContext(context.Background())
+
+	g.Go(func() error { return errFirst })
+	g.Go(func() error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	if err := g.Wait(); !errors.Is(err, errFirst) {
+		t.Errorf("expected first error, got %v", err)
+	}
+	if !errors.Is(context.Cause(ctx), errFirst) {
+		t.Errorf("expected context cause to be the first error, got %v", context.Cause(ctx))
+	}
+}
+
+func TestGroup_SetLimit(t *testing.T) {
+	g := NewGroup()
+	g.SetLimit(2)
+
+	var current, maxObserved int64
+	for range 10 {
+		g.Go(func() error {
+			cur := atomic.AddInt64(&current, 1)
+			for {
+				old := atomic.LoadInt64(&maxObserved)
+				if cur <= old || atomic.CompareAndSwapInt64(&maxObserved, old, cur) {
+					break
+				}
+			}
+			time.Sleep(2 * time.Millisecond)
+			atomic.AddInt64(&current, -1)
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if maxObserved > 2 {
+		t.Errorf("max concurrent = %d, exceeded limit of 2", maxObserved)
+	}
+}
+
+func TestGroup_TryGo(t *testing.T) {
+	g := NewGroup()
+	g.SetLimit(1)
+
+	release := make(chan struct{})
+	if !g.TryGo(func() error { <-release; return nil }) {
+		t.Fatal("expected first TryGo to start")
+	}
+	if g.TryGo(func() error { return nil }) {
+		t.Error("expected TryGo to fail at the limit")
+	}
+	close(release)
+	if err := g.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !g.TryGo(func() error { return nil }) {
+		t.Error("expected TryGo to start after Wait")
+	}
+	_ = g.Wait()
+}
+
+func TestGroup_SetLimitZero(t *testing.T) {
+	g := NewGroup()
+	g.SetLimit(0)
+
+	if g.TryGo(func() error { return nil }) {
+		t.Error("expected TryGo to fail with a zero limit")
+	}
+
+	started := make(chan struct{})
+	go func() {
+		g.Go(func() error { return nil })
+		close(started)
+	}()
+	select {
+	case <-started:
+		t.Error("expected Go to block with a zero limit")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestGroup_PanicErrors(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	g := NewGroup(WithRecover(logger), WithPanicErrors())
+	g.Go(func() error { panic("boom") })
+
+	var pe *PanicError
+	if err := g.Wait(); !errors.As(err, &pe) || pe.Value != "boom" {
+		t.Errorf("expected PanicError, got %v", err)
+	}
+}
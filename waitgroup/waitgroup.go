@@ -2,6 +2,9 @@ package waitgroup
 
 import (
 	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
 	"sync"
 )
 
@@ -15,17 +18,64 @@ type WaitGroup interface {
 	WithWaitGroup(wg *sync.WaitGroup) WaitGroup
 }
 
+// TaskGroup is a WaitGroup that also starts tasks, see LimitWaitGroup.Go.
+type TaskGroup interface {
+	WaitGroup
+	Go(fn func())
+	WaitErr() error
+}
+
+// PanicError is a panic recovered from a task started with Go.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("waitgroup: task panicked: %v", e.Value)
+}
+
 type LimitWaitGroup struct {
 	wg    sync.WaitGroup
 	limit chan struct{}
+
+	recoverPanics bool
+	logger        *slog.Logger
+	panicErrors   bool
+
+	mu   sync.Mutex
+	errs []error
+}
+
+type Option func(*LimitWaitGroup)
+
+// WithRecover recovers panics in tasks started with Go and logs them with
+// their stack trace. A nil logger uses slog.Default().
+func WithRecover(logger *slog.Logger) Option {
+	return func(w *LimitWaitGroup) {
+		w.recoverPanics = true
+		w.logger = logger
+	}
+}
+
+// WithPanicErrors recovers and logs panics like WithRecover, and also records
+// them as *PanicError values returned by WaitErr.
+func WithPanicErrors() Option {
+	return func(w *LimitWaitGroup) {
+		w.recoverPanics = true
+		w.panicErrors = true
+	}
 }
 
 // NewLimitWaitGroup creates a new LimitWaitGroup with no limit.
-func NewLimitWaitGroup(limit int) WaitGroup {
+func NewLimitWaitGroup(limit int, opts ...Option) TaskGroup {
 	lwg := &LimitWaitGroup{
 		wg:    sync.WaitGroup{},
 		limit: make(chan struct{}, limit),
 	}
+	for _, opt := range opts {
+		opt(lwg)
+	}
 	return lwg
 }
 
@@ -60,3 +110,75 @@ func (w *LimitWaitGroup) Done() {
 func (w *LimitWaitGroup) Wait() {
 	w.wg.Wait()
 }
+
+// Go runs fn in a new goroutine, blocking until the limit allows it to start.
+// Panics are recovered when the group was created with WithRecover or
+// WithPanicErrors; otherwise they crash the program as usual.
+func (w *LimitWaitGroup) Go(fn func()) {
+	w.Add(1)
+	w.start(fn)
+}
+
+// addOne adds one task, blocking until the limit allows it. Unlike Add(1) it
+// does not panic on a zero limit; it blocks forever, as errgroup does.
+func (w *LimitWaitGroup) addOne() {
+	if w.limit != nil {
+		w.limit <- struct{}{}
+	}
+	w.wg.Add(1)
+}
+
+// tryAdd adds one task if the limit allows it without blocking.
+func (w *LimitWaitGroup) tryAdd() bool {
+	if w.limit != nil {
+		select {
+		case w.limit <- struct{}{}:
+		default:
+			return false
+		}
+	}
+	w.wg.Add(1)
+	return true
+}
+
+// start runs fn in a new goroutine for a task already counted by Add.
+func (w *LimitWaitGroup) start(fn func()) {
+	go func() {
+		defer w.Done()
+		if w.recoverPanics {
+			defer w.recoverPanic()
+		}
+		fn()
+	}()
+}
+
+// WaitErr waits for all tasks and returns the panics recorded by
+// WithPanicErrors since the last WaitErr, joined into a single error.
+func (w *LimitWaitGroup) WaitErr() error {
+	w.Wait()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	err := errors.Join(w.errs...)
+	w.errs = nil
+	return err
+}
+
+func (w *LimitWaitGroup) recoverPanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	stack := debug.Stack()
+
+	logger := w.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.Error("waitgroup: recovered panic", "panic", r, "stack", string(stack))
+
+	if w.panicErrors {
+		w.mu.Lock()
+		w.errs = append(w.errs, &PanicError{Value: r, Stack: stack})
+		w.mu.Unlock()
+	}
+}
@@ -1,6 +1,11 @@
 package waitgroup
 
 import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -236,3 +241,81 @@ func TestAddExceedsLimit_Panics(t *testing.T) {
 	wg := NewLimitWaitGroup(3)
 	wg.Add(4)
 }
+
+func TestGo_RunsTasks(t *testing.T) {
+	wg := NewLimitWaitGroup(3)
+
+	var counter int64
+	for i := 0; i < 10; i++ {
+		wg.Go(func() {
+			atomic.AddInt64(&counter, 1)
+		})
+	}
+
+	if err := wg.WaitErr(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if counter != 10 {
+		t.Errorf("expected counter = 10, got %d", counter)
+	}
+}
+
+func TestGo_WithRecover_LogsPanic(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	wg := NewLimitWaitGroup(2, WithRecover(logger))
+
+	wg.Go(func() { panic("boom") })
+	wg.Go(func() {})
+
+	if err := wg.WaitErr(); err != nil {
+		t.Fatalf("WithRecover alone should not surface errors, got %v", err)
+	}
+	output := buf.String()
+	if !strings.Contains(output, "boom") {
+		t.Errorf("expected panic value in log output, got %q", output)
+	}
+	if !strings.Contains(output, "stack") {
+		t.Errorf("expected stack trace in log output, got %q", output)
+	}
+}
+
+func TestGo_WithPanicErrors(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	wg := NewLimitWaitGroup(2, WithRecover(logger), WithPanicErrors())
+
+	wg.Go(func() { panic("first") })
+	wg.Go(func() { panic("second") })
+	wg.Go(func() {})
+
+	err := wg.WaitErr()
+	if err == nil {
+		t.Fatal("expected error from panicking tasks")
+	}
+
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected *PanicError, got %T", err)
+	}
+	if len(pe.Stack) == 0 {
+		t.Error("expected stack trace on PanicError")
+	}
+	if !strings.Contains(err.Error(), "first") || !strings.Contains(err.Error(), "second") {
+		t.Errorf("expected both panics in error, got %q", err.Error())
+	}
+}
+
+func TestWaitErr_ResetsAfterCollecting(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	wg := NewLimitWaitGroup(2, WithRecover(logger), WithPanicErrors())
+
+	wg.Go(func() { panic("first") })
+	if err := wg.WaitErr(); err == nil {
+		t.Fatal("expected error from panicking task")
+	}
+
+	wg.Go(func() {})
+	if err := wg.WaitErr(); err != nil {
+		t.Errorf("expected reused group to report no stale panics, got %v", err)
+	}
+}